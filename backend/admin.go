@@ -0,0 +1,245 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "battle-arena/message"
+)
+
+// The admin API is only mounted when ADMIN_TOKEN is set. Requests must send
+// "Authorization: Bearer <ADMIN_TOKEN>".
+
+type adminPosition struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type adminPlayer struct {
+	ID        int32         `json:"id"`
+	Name      string        `json:"name"`
+	Color     string        `json:"color"`
+	Position  adminPosition `json:"position"`
+	Rotation  float64       `json:"rotation"`
+	Health    int32         `json:"health"`
+	Kills     int32         `json:"kills"`
+	Deaths    int32         `json:"deaths"`
+	IsDead    bool          `json:"isDead"`
+	Team      int32         `json:"team"`
+	Score     int32         `json:"score"`
+	Weapon    string        `json:"weapon"`
+	Ammo      int32         `json:"ammo"`
+	Reserve   int32         `json:"reserve"`
+	Weapons   []string      `json:"weapons"`
+	Shield    int32         `json:"shield"`
+	Bot       bool          `json:"bot"`
+	IsReady   bool          `json:"isReady"`
+	InGrass   bool          `json:"inGrass"`
+	Connected bool          `json:"connected"`
+}
+
+type adminRoom struct {
+	ID            uint16        `json:"id"`
+	IsGameStarted bool          `json:"isGameStarted"`
+	Map           string        `json:"map"`
+	Mode          string        `json:"mode"`
+	CreatedAt     time.Time     `json:"createdAt"`
+	AgeSeconds    float64       `json:"ageSeconds"`
+	Players       []adminPlayer `json:"players"`
+	Spectators    int           `json:"spectators"`
+}
+
+func registerAdminRoutes(mux *http.ServeMux) {
+	token := os.Getenv("ADMIN_TOKEN")
+	if token == "" {
+		return
+	}
+
+	admin := http.NewServeMux()
+	admin.HandleFunc("GET /admin/rooms", adminListRooms)
+	admin.HandleFunc("GET /admin/rooms/{roomId}", adminGetRoom)
+	admin.HandleFunc("POST /admin/rooms/{roomId}/end", adminEndRoom)
+	admin.HandleFunc("POST /admin/rooms/{roomId}/players/{playerId}/kick", adminKickPlayer)
+	admin.HandleFunc("POST /admin/rooms/{roomId}/broadcast", adminBroadcastRoom)
+	admin.HandleFunc("POST /admin/broadcast", adminBroadcastAll)
+
+	mux.Handle("/admin/", requireAdminToken(token, admin))
+}
+
+func requireAdminToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (room *Room) toAdmin() adminRoom {
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+
+	var data = adminRoom{
+		ID:            room.ID,
+		IsGameStarted: room.IsGameStarted,
+		Map:           room.gameMap.Name,
+		Mode:          room.settings.Mode,
+		CreatedAt:     room.CreatedAt,
+		AgeSeconds:    time.Since(room.CreatedAt).Seconds(),
+		Players:       []adminPlayer{},
+		Spectators:    len(room.spectators),
+	}
+
+	for _, player := range room.player {
+		if player == nil {
+			continue
+		}
+		player.mu.RLock()
+		data.Players = append(data.Players, adminPlayer{
+			ID:        player.Id,
+			Name:      player.Name,
+			Color:     player.Color,
+			Position:  adminPosition{X: player.Position.GetX(), Y: player.Position.GetY()},
+			Rotation:  player.Rotation,
+			Health:    player.Health,
+			Kills:     player.Kills,
+			Deaths:    player.Deaths,
+			IsDead:    player.IsDead,
+			Team:      player.Team,
+			Score:     player.Score,
+			Weapon:    player.Weapon,
+			Ammo:      player.Ammo,
+			Reserve:   player.Reserve,
+			Weapons:   slices.Clone(player.Weapons),
+			Shield:    player.Shield,
+			Bot:       player.IsBot,
+			IsReady:   player.IsReady,
+			InGrass:   player.InGrass,
+			Connected: player.Conn != nil,
+		})
+		player.mu.RUnlock()
+	}
+	return data
+}
+
+func adminLoadRoom(w http.ResponseWriter, r *http.Request) *Room {
+	roomID, err := strconv.Atoi(r.PathValue("roomId"))
+	if err != nil {
+		http.Error(w, "Invalid Room Id", http.StatusBadRequest)
+		return nil
+	}
+	room, ok := rooms.Load(uint16(roomID))
+	if !ok {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return nil
+	}
+	return room.(*Room)
+}
+
+func adminListRooms(w http.ResponseWriter, r *http.Request) {
+	var list = []adminRoom{}
+	rooms.Range(func(_, value any) bool {
+		list = append(list, value.(*Room).toAdmin())
+		return true
+	})
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+func adminGetRoom(w http.ResponseWriter, r *http.Request) {
+	room := adminLoadRoom(w, r)
+	if room == nil {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(room.toAdmin())
+}
+
+func adminEndRoom(w http.ResponseWriter, r *http.Request) {
+	room := adminLoadRoom(w, r)
+	if room == nil {
+		return
+	}
+	room.logger().Info("match force-ended by admin")
+	go room.broadcastGameOver()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func adminKickPlayer(w http.ResponseWriter, r *http.Request) {
+	room := adminLoadRoom(w, r)
+	if room == nil {
+		return
+	}
+
+	playerID, err := strconv.Atoi(r.PathValue("playerId"))
+	if err != nil || playerID < 0 || playerID >= len(room.player) {
+		http.Error(w, "Invalid Player Id", http.StatusBadRequest)
+		return
+	}
+
+	room.mu.RLock()
+	exists := room.player[playerID] != nil
+	room.mu.RUnlock()
+	if !exists {
+		http.Error(w, "Player not found", http.StatusNotFound)
+		return
+	}
+
+	var ID = int32(playerID)
+	room.playerLogger(ID).Info("player kicked by admin")
+	go room.post(&pb.Message{
+		Id:    &ID,
+		Event: KICK,
+	})
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func decodeServerMessage(w http.ResponseWriter, r *http.Request) *pb.Message {
+	var body struct {
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Message == "" {
+		http.Error(w, "Invalid Inputs", http.StatusBadRequest)
+		return nil
+	}
+	return &pb.Message{
+		Event:   SERVER_MESSAGE,
+		Payload: &pb.Payload{Text: &body.Message},
+	}
+}
+
+func adminBroadcastRoom(w http.ResponseWriter, r *http.Request) {
+	room := adminLoadRoom(w, r)
+	if room == nil {
+		return
+	}
+	msg := decodeServerMessage(w, r)
+	if msg == nil {
+		return
+	}
+	go room.broadcastParallel(msg)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func adminBroadcastAll(w http.ResponseWriter, r *http.Request) {
+	msg := decodeServerMessage(w, r)
+	if msg == nil {
+		return
+	}
+	rooms.Range(func(_, value any) bool {
+		go value.(*Room).broadcastParallel(msg)
+		return true
+	})
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+// Bot difficulties
+const (
+	BOT_EASY   = "easy"
+	BOT_MEDIUM = "medium"
+	BOT_HARD   = "hard"
+)
+
+const (
+	BOT_TICK         = sim.TICK // how often a bot moves, like a client
+	BOT_WANDER_RANGE = 400
+	BOT_COVER_RANGE  = 300
+	BOT_SAMPLES      = 16
+)
+
+var BOT_COLORS = []string{"#ff0000", "#00ffff", "#ffb8ff", "#ffb852", "#00ff00", "#ff69b4"}
+
+type botDifficulty struct {
+	inaccuracy  float64       // largest aiming error in radians
+	reaction    time.Duration // time between decisions
+	sightRange  float64
+	coverHealth int32 // takes cover at or below this health
+}
+
+var BOT_DIFFICULTIES = map[string]*botDifficulty{
+	BOT_EASY:   {inaccuracy: 0.35, reaction: 700 * time.Millisecond, sightRange: 450, coverHealth: 0},
+	BOT_MEDIUM: {inaccuracy: 0.15, reaction: 400 * time.Millisecond, sightRange: 650, coverHealth: 30},
+	BOT_HARD:   {inaccuracy: 0.05, reaction: 200 * time.Millisecond, sightRange: 900, coverHealth: 50},
+}
+
+// botBrain is the AI state of a bot. Only the bot's own runBot goroutine
+// touches it once the bot is in the room.
+type botBrain struct {
+	difficulty   string
+	destination  *pb.Position
+	path         []*pb.Position // waypoints to destination, next one first
+	pathTo       *pb.Position   // the destination path leads to
+	enemy        *pb.Position
+	enemyDist    float64
+	aim          float64
+	retreating   bool
+	nextDecision time.Time
+	lastShot     time.Time
+	lastPosition *pb.Position
+	stuck        int
+}
+
+// botView is what a bot knows about itself when it decides.
+type botView struct {
+	ID        int32
+	position  *pb.Position
+	health    int32
+	team      int32
+	weapon    *sim.Weapon
+	weapons   []string
+	ammo      int32
+	reserve   int32
+	reloading bool
+}
+
+func (player *Player) isBot() bool {
+	return player.bot != nil
+}
+
+// hasHumans reports whether anyone but bots and player leaving is still in
+// the room. The caller must hold room.mu.
+func (room *Room) hasHumans(leaving int32) bool {
+	for _, player := range room.player {
+		if player != nil && player.Id != leaving && !player.isBot() {
+			return true
+		}
+	}
+	return false
+}
+
+// addBot handles an ADD_BOT request from the host in the lobby. The bot takes
+// a free slot like a joining player and is always ready.
+func (room *Room) addBot(msg *pb.Message) {
+	if *msg.Id != 0 {
+		return
+	}
+	var difficulty = BOT_MEDIUM
+	if msg.Payload != nil && msg.Payload.Difficulty != nil {
+		difficulty = *msg.Payload.Difficulty
+	}
+	if _, ok := BOT_DIFFICULTIES[difficulty]; !ok {
+		return
+	}
+
+	room.mu.Lock()
+	var slot = slices.Index(room.player[:], nil)
+	if room.IsGameStarted || slot < 0 {
+		room.mu.Unlock()
+		return
+	}
+	var ID = int32(slot)
+	var bot = Player{bot: &botBrain{difficulty: difficulty}}
+	initializePlayer(&bot, ID, room.spawnPosition(ID))
+	bot.Name = fmt.Sprintf("Bot %d", ID)
+	bot.Color = BOT_COLORS[slot%len(BOT_COLORS)]
+	bot.IsBot = true
+	bot.IsReady = true
+	if room.settings.Teams {
+		bot.Team = room.balancedTeam(ID)
+	}
+	room.player[ID] = &bot
+
+	var message = pb.Message{
+		Id:      &ID,
+		Event:   JOIN,
+		Payload: &pb.Payload{Players: []*pb.Player{}},
+	}
+	for _, player := range room.player {
+		if player != nil {
+			player.mu.RLock()
+			message.Payload.Players = append(message.Payload.Players, player.toProto())
+			player.mu.RUnlock()
+		}
+	}
+	room.mu.Unlock()
+
+	room.playerLogger(ID).Info("bot joined", "difficulty", difficulty)
+	go room.broadcastParallel(&message)
+}
+
+// removeBot handles a REMOVE_BOT request from the host in the lobby.
+func (room *Room) removeBot(msg *pb.Message) {
+	if *msg.Id != 0 || msg.Payload == nil || msg.Payload.Target == nil {
+		return
+	}
+	var ID = *msg.Payload.Target
+	if ID < 0 || int(ID) >= len(room.player) {
+		return
+	}
+
+	room.mu.RLock()
+	var player = room.player[ID]
+	var isBot = player != nil && player.isBot() && !room.IsGameStarted
+	room.mu.RUnlock()
+	if isBot {
+		room.kickPlayer(&pb.Message{Id: &ID, Event: KICK})
+	}
+}
+
+// runBot drives bot ID for the rest of the match. Its moves, shots and
+// weapon switches go into room.broadcast exactly like a client's messages.
+func (room *Room) runBot(ID int32) {
+	ticker := time.NewTicker(BOT_TICK)
+	defer ticker.Stop()
+	for range ticker.C {
+		messages, ok := room.thinkBot(ID)
+		if !ok {
+			return
+		}
+		for _, msg := range messages {
+			if !room.post(msg) {
+				return
+			}
+		}
+	}
+}
+
+// thinkBot runs one tick of bot ID's AI and returns the messages it sends.
+// It reports false once the bot is gone or the match is over.
+func (room *Room) thinkBot(ID int32) ([]*pb.Message, bool) {
+	room.mu.RLock()
+	var self = room.player[ID]
+	if room.isOver || self == nil || !self.isBot() {
+		room.mu.RUnlock()
+		return nil, false
+	}
+
+	self.mu.RLock()
+	var view = botView{
+		ID:        ID,
+		position:  self.Position,
+		health:    self.Health,
+		team:      self.Team,
+		weapon:    sim.WeaponByName(self.Weapon),
+		weapons:   slices.Clone(self.Weapons),
+		ammo:      self.Ammo,
+		reserve:   self.Reserve,
+		reloading: self.Reloading,
+	}
+	var isDead = self.IsDead
+	self.mu.RUnlock()
+	if isDead || view.position == nil {
+		room.mu.RUnlock()
+		return nil, true
+	}
+
+	var brain = self.bot
+	var now = time.Now()
+	var messages []*pb.Message
+	if now.After(brain.nextDecision) {
+		brain.nextDecision = now.Add(BOT_DIFFICULTIES[brain.difficulty].reaction)
+		messages = append(messages, room.decideBot(brain, &view)...)
+	}
+	room.mu.RUnlock()
+
+	// only this bot's goroutine touches its brain, and path finding reads
+	// nothing but the room's grid, so it runs without holding up the room
+	if brain.destination != brain.pathTo {
+		brain.pathTo = brain.destination
+		brain.path = nil
+		if brain.destination != nil {
+			brain.path = room.nav.findPath(view.position, brain.destination)
+		}
+	}
+	return append(messages, brain.act(&view, now)...), true
+}
+
+// decideBot picks the bot's enemy, aim and destination. The caller holds
+// room.mu.
+func (room *Room) decideBot(brain *botBrain, view *botView) []*pb.Message {
+	var difficulty = BOT_DIFFICULTIES[brain.difficulty]
+	var messages []*pb.Message
+
+	brain.enemy = room.nearestVisibleEnemy(view, difficulty.sightRange)
+	if brain.enemy == nil {
+		brain.retreating = false
+		if brain.destination == nil {
+			brain.destination = room.wanderDestination(view.position)
+		}
+		return nil
+	}
+
+	brain.enemyDist = math.Hypot(brain.enemy.X-view.position.X, brain.enemy.Y-view.position.Y)
+	brain.aim = math.Atan2(brain.enemy.Y-view.position.Y, brain.enemy.X-view.position.X) +
+		(rand.Float64()*2-1)*difficulty.inaccuracy
+
+	if weapon := bestWeapon(view.weapons, brain.enemyDist); weapon != view.weapon.Name {
+		messages = append(messages, &pb.Message{
+			Id:      &view.ID,
+			Event:   WEAPON,
+			Payload: &pb.Payload{Weapon: &weapon},
+		})
+	}
+
+	// a bot that is out of ammo runs for the nearest pickup
+	var outOfAmmo = view.ammo == 0 && view.reserve == 0 && !view.reloading
+	brain.retreating = view.health <= difficulty.coverHealth || outOfAmmo
+	if outOfAmmo {
+		brain.destination = room.nearestPickup(view.position)
+	} else if brain.retreating {
+		brain.destination = room.coverFrom(view.position, brain.enemy)
+	} else if brain.enemyDist > view.weapon.Range*0.6 {
+		brain.destination = brain.enemy
+	} else {
+		// strafe around the enemy instead of standing still
+		var side = math.Pi / 2
+		if rand.Intn(2) == 0 {
+			side = -side
+		}
+		var angle = brain.aim + side
+		brain.destination = &pb.Position{
+			X: view.position.X + math.Cos(angle)*BOT_WANDER_RANGE/4,
+			Y: view.position.Y + math.Sin(angle)*BOT_WANDER_RANGE/4,
+		}
+	}
+	return messages
+}
+
+// act turns the bot's current plan into this tick's messages: a move and a
+// shot when it's fighting, otherwise a move along its path.
+func (brain *botBrain) act(view *botView, now time.Time) []*pb.Message {
+	if brain.lastPosition != nil && brain.lastPosition.X == view.position.X && brain.lastPosition.Y == view.position.Y {
+		brain.stuck++
+	} else {
+		brain.stuck = 0
+	}
+	brain.lastPosition = view.position
+
+	if brain.enemy != nil && !brain.retreating && !view.reloading && view.ammo > 0 &&
+		now.Sub(brain.lastShot) >= view.weapon.FireRate {
+		brain.lastShot = now
+		// turning towards the target is a step in its direction, as it is
+		// for players
+		return []*pb.Message{
+			{Id: &view.ID, Event: MOVE, Payload: &pb.Payload{Position: &pb.Position{X: math.Cos(brain.aim), Y: math.Sin(brain.aim)}}},
+			{Id: &view.ID, Event: SHOOT, Payload: &pb.Payload{}},
+		}
+	}
+
+	for len(brain.path) > 0 && math.Hypot(brain.path[0].X-view.position.X, brain.path[0].Y-view.position.Y) < 2*sim.PLAYER_SPEED {
+		brain.path = brain.path[1:]
+	}
+	if len(brain.path) == 0 || brain.stuck > 10 {
+		// arrived, stuck or no way there: decide somewhere else to go
+		brain.destination = nil
+		brain.path = nil
+		brain.stuck = 0
+		return nil
+	}
+	var dx, dy = brain.path[0].X - view.position.X, brain.path[0].Y - view.position.Y
+	return []*pb.Message{{Id: &view.ID, Event: MOVE, Payload: &pb.Payload{Position: &pb.Position{X: dx, Y: dy}}}}
+}
+
+// nearestVisibleEnemy returns where the closest enemy the bot can see is, or
+// nil. Players in grass are only seen up close. The caller holds room.mu.
+func (room *Room) nearestVisibleEnemy(view *botView, sightRange float64) *pb.Position {
+	var nearest *pb.Position
+	var nearestDistance = sightRange
+	for _, player := range room.player {
+		if player == nil || player.Id == view.ID {
+			continue
+		}
+		player.mu.RLock()
+		var position, inGrass = player.Position, player.InGrass
+		var hidden = player.IsDead || position == nil ||
+			(room.settings.Teams && player.Team == view.team)
+		player.mu.RUnlock()
+		if hidden {
+			continue
+		}
+
+		var distance = math.Hypot(position.X-view.position.X, position.Y-view.position.Y)
+		if distance > nearestDistance || !isVisible(room.obstacles, view.position, position, inGrass) {
+			continue
+		}
+		nearest, nearestDistance = position, distance
+	}
+	return nearest
+}
+
+// nearestPickup returns where the closest available pickup is, or nil.
+func (room *Room) nearestPickup(from *pb.Position) *pb.Position {
+	var nearest *pb.Position
+	var nearestDistance = math.Inf(1)
+	for _, pickup := range room.pickups.toProto() {
+		distance := math.Hypot(pickup.Position.X-from.X, pickup.Position.Y-from.Y)
+		if pickup.Available && distance < nearestDistance {
+			nearest, nearestDistance = pickup.Position, distance
+		}
+	}
+	return nearest
+}
+
+// wanderDestination picks somewhere nearby to walk to, often a grass patch
+// to lurk in.
+func (room *Room) wanderDestination(from *pb.Position) *pb.Position {
+	if len(room.gameMap.GrassPatches) > 0 && rand.Intn(3) == 0 {
+		var nearest *pb.GrassPatch
+		var nearestDistance = math.Inf(1)
+		for _, grass := range room.gameMap.GrassPatches {
+			distance := math.Hypot(float64(grass.X)-from.X, float64(grass.Y)-from.Y)
+			if distance > sim.PLAYER_SIZE && distance < nearestDistance {
+				nearest, nearestDistance = grass, distance
+			}
+		}
+		if nearest != nil {
+			return &pb.Position{X: float64(nearest.X), Y: float64(nearest.Y)}
+		}
+	}
+	for i := 0; i < BOT_SAMPLES; i++ {
+		candidate := &pb.Position{
+			X: from.X + (rand.Float64()*2-1)*BOT_WANDER_RANGE,
+			Y: from.Y + (rand.Float64()*2-1)*BOT_WANDER_RANGE,
+		}
+		if !room.obstacles.IsBlocked(sim.PLAYER_SIZE, candidate) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// coverFrom finds the closest nearby spot the enemy can't see: behind an
+// obstacle or in grass. Without one, the bot runs straight away.
+func (room *Room) coverFrom(from, enemy *pb.Position) *pb.Position {
+	var best *pb.Position
+	var bestDistance = math.Inf(1)
+	var consider = func(candidate *pb.Position) {
+		distance := math.Hypot(candidate.X-from.X, candidate.Y-from.Y)
+		if distance < bestDistance && !room.obstacles.IsBlocked(sim.PLAYER_SIZE, candidate) {
+			best, bestDistance = candidate, distance
+		}
+	}
+
+	for _, grass := range room.gameMap.GrassPatches {
+		candidate := &pb.Position{X: float64(grass.X), Y: float64(grass.Y)}
+		if math.Hypot(candidate.X-from.X, candidate.Y-from.Y) < BOT_COVER_RANGE &&
+			math.Hypot(candidate.X-enemy.X, candidate.Y-enemy.Y) > REVEAL_DISTANCE {
+			consider(candidate)
+		}
+	}
+	for i := 0; i < BOT_SAMPLES; i++ {
+		var angle = rand.Float64() * 2 * math.Pi
+		var distance = rand.Float64() * BOT_COVER_RANGE
+		candidate := &pb.Position{X: from.X + math.Cos(angle)*distance, Y: from.Y + math.Sin(angle)*distance}
+		if !room.obstacles.HasLineOfSight(enemy, candidate) {
+			consider(candidate)
+		}
+	}
+	if best != nil {
+		return best
+	}
+
+	var away = math.Atan2(from.Y-enemy.Y, from.X-enemy.X)
+	return &pb.Position{X: from.X + math.Cos(away)*BOT_COVER_RANGE, Y: from.Y + math.Sin(away)*BOT_COVER_RANGE}
+}
+
+// bestWeapon picks the carried weapon that suits the distance to the enemy.
+func bestWeapon(weapons []string, distance float64) string {
+	var preference = []string{sim.WEAPON_SMG, sim.WEAPON_PISTOL, sim.WEAPON_SHOTGUN, sim.WEAPON_SNIPER}
+	if distance < 200 {
+		preference = []string{sim.WEAPON_SHOTGUN, sim.WEAPON_SMG, sim.WEAPON_PISTOL, sim.WEAPON_SNIPER}
+	} else if distance > 500 {
+		preference = []string{sim.WEAPON_SNIPER, sim.WEAPON_PISTOL, sim.WEAPON_SMG, sim.WEAPON_SHOTGUN}
+	}
+	for _, name := range preference {
+		if slices.Contains(weapons, name) {
+			return name
+		}
+	}
+	return sim.DEFAULT_WEAPON
+}
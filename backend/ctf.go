@@ -0,0 +1,199 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+const (
+	FLAG_RADIUS           = 25
+	FLAG_RETURN_TIMEOUT   = 15 * time.Second
+	CARRIER_SPEED_FACTOR  = 0.75
+	DEFAULT_CAPTURE_LIMIT = 3
+)
+
+type flagState struct {
+	team     int32
+	home     *pb.Position
+	position *pb.Position
+	carrier  *int32
+	atHome   bool
+	// bumped whenever the flag moves so a stale return timer does nothing
+	generation uint64
+}
+
+// ctfState holds both flags and the capture score. Its lock is only ever
+// taken after room.mu and player.mu, and never while acquiring either.
+type ctfState struct {
+	flags    [sim.TEAM_COUNT]*flagState
+	captures [sim.TEAM_COUNT]int32
+	mu       sync.Mutex
+}
+
+func (room *Room) isCaptureTheFlag() bool {
+	return room.settings.Mode == sim.MODE_CAPTURE_THE_FLAG
+}
+
+// newCTFState places each team's flag on its base. It returns nil when the
+// map doesn't have a base for every team.
+func newCTFState(gameMap *pb.GameMap) *ctfState {
+	var state ctfState
+	for _, base := range gameMap.FlagBases {
+		if !sim.IsValidTeam(base.Team) || state.flags[base.Team-1] != nil {
+			continue
+		}
+		state.flags[base.Team-1] = &flagState{
+			team:     base.Team,
+			home:     base.Position,
+			position: base.Position,
+			atHome:   true,
+		}
+	}
+	for _, flag := range state.flags {
+		if flag == nil {
+			return nil
+		}
+	}
+	return &state
+}
+
+func (flag *flagState) toProto() *pb.Flag {
+	return &pb.Flag{
+		Team:     flag.team,
+		Position: flag.position,
+		Carrier:  flag.carrier,
+		AtHome:   flag.atHome,
+	}
+}
+
+func (state *ctfState) toProto() []*pb.Flag {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	var flags []*pb.Flag
+	for _, flag := range state.flags {
+		flags = append(flags, flag.toProto())
+	}
+	return flags
+}
+
+func (state *ctfState) carrying(ID int32) bool {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	for _, flag := range state.flags {
+		if flag.carrier != nil && *flag.carrier == ID {
+			return true
+		}
+	}
+	return false
+}
+
+// updateFlags runs after a player moves: it picks up the enemy flag on
+// touch, keeps a carried flag on its carrier, and scores a capture when the
+// carrier reaches their own base while their flag is home. The caller holds
+// room.mu but not the player's lock.
+func (room *Room) updateFlags(ID int32, team int32, position *pb.Position) {
+	if !room.isCaptureTheFlag() || !sim.IsValidTeam(team) {
+		return
+	}
+	var state = room.ctf
+	var event string
+	var ownCaptures int32
+
+	state.mu.Lock()
+	var own = state.flags[team-1]
+	for _, flag := range state.flags {
+		if flag.team == team {
+			continue
+		}
+		if flag.carrier != nil && *flag.carrier == ID {
+			flag.position = position
+			if sim.Touches(position, own.home, FLAG_RADIUS) && own.atHome {
+				flag.carrier = nil
+				flag.position = flag.home
+				flag.atHome = true
+				flag.generation++
+				state.captures[team-1]++
+				ownCaptures = state.captures[team-1]
+				event = FLAG_CAPTURED
+			}
+		} else if flag.carrier == nil && sim.Touches(position, flag.position, FLAG_RADIUS) {
+			var carrier = ID
+			flag.carrier = &carrier
+			flag.position = position
+			flag.atHome = false
+			flag.generation++
+			event = FLAG_TAKEN
+		}
+	}
+	state.mu.Unlock()
+
+	if event == "" {
+		return
+	}
+
+	room.playerLogger(ID).Info("flag event", "event", event, "team", team)
+	go room.broadcastParallel(&pb.Message{
+		Id:      &ID,
+		Event:   event,
+		Payload: &pb.Payload{Flags: state.toProto(), TeamScores: room.teamScores()},
+	})
+
+	if event == FLAG_CAPTURED && ownCaptures >= room.settings.CaptureLimit {
+		room.logger().Info("capture limit reached", "team", team)
+		go room.broadcastGameOver()
+	}
+}
+
+// dropFlag leaves any flag carried by player ID where they died or left and
+// sends it home if nobody picks it up within FLAG_RETURN_TIMEOUT.
+func (room *Room) dropFlag(ID int32, position *pb.Position) {
+	if !room.isCaptureTheFlag() {
+		return
+	}
+	var state = room.ctf
+
+	state.mu.Lock()
+	var dropped *flagState
+	for _, flag := range state.flags {
+		if flag.carrier != nil && *flag.carrier == ID {
+			flag.carrier = nil
+			flag.position = position
+			flag.generation++
+			dropped = flag
+		}
+	}
+	if dropped == nil {
+		state.mu.Unlock()
+		return
+	}
+	var generation = dropped.generation
+	state.mu.Unlock()
+
+	room.playerLogger(ID).Info("flag event", "event", FLAG_DROPPED, "team", dropped.team)
+	go room.broadcastParallel(&pb.Message{
+		Id:      &ID,
+		Event:   FLAG_DROPPED,
+		Payload: &pb.Payload{Flags: state.toProto()},
+	})
+
+	time.AfterFunc(FLAG_RETURN_TIMEOUT, func() {
+		state.mu.Lock()
+		if dropped.generation != generation || dropped.carrier != nil {
+			state.mu.Unlock()
+			return
+		}
+		dropped.position = dropped.home
+		dropped.atHome = true
+		dropped.generation++
+		state.mu.Unlock()
+
+		room.logger().Info("flag event", "event", FLAG_RETURNED, "team", dropped.team)
+		go room.broadcastParallel(&pb.Message{
+			Event:   FLAG_RETURNED,
+			Payload: &pb.Payload{Flags: state.toProto()},
+		})
+	})
+}
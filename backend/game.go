@@ -1,120 +1,74 @@
 package main
 
 import (
-	"math"
-	"math/rand"
 	"net"
 	"sync"
 	"time"
 
+	"google.golang.org/protobuf/proto"
+
 	pb "battle-arena/message"
+	"battle-arena/sim"
 )
 
 const (
-	PLAYER_SPEED      = 4
-	BULLET_SPEED      = 7
-	GRASS_MIN_RADIUS  = 30
-	GRASS_MAX_RADIUS  = 50
-	NUM_GRASS_PATCHES = 20
-	MAP_WIDTH         = 2000
-	MAP_HEIGHT        = 1500
-	PLAYER_SIZE       = 20
-	BULLET_SIZE       = 4
+	GRASS_MIN_RADIUS = 30
+	GRASS_MAX_RADIUS = 50
+	MAP_WIDTH        = 2000
+	MAP_HEIGHT       = 1500
 )
 
 type Player struct {
 	pb.Player
 	Conn *net.Conn
-	mu   sync.RWMutex
-}
-
-var gameMap = generateMap()
-
-func generateMap() *pb.GameMap {
-	var Map pb.GameMap
-	Map.Obstacles = []*pb.Obstacle{
-		{X: MAP_WIDTH/2 - 100, Y: MAP_HEIGHT/2 - 100, Width: 200, Height: 200},
-
-		// Corner obstacles
-		{X: 50, Y: 50, Width: 100, Height: 100},
-		{X: MAP_WIDTH - 150, Y: 50, Width: 100, Height: 100},
-		{X: 50, Y: MAP_HEIGHT - 150, Width: 100, Height: 100},
-		{X: MAP_WIDTH - 150, Y: MAP_HEIGHT - 150, Width: 100, Height: 100},
-	}
-
-	for i := 0; i < NUM_GRASS_PATCHES; i++ {
-		Map.GrassPatches = append(Map.GrassPatches, &pb.GrassPatch{
-			X:      uint32(rand.Float64() * float64(MAP_WIDTH)),
-			Y:      uint32(rand.Float64() * float64(MAP_HEIGHT)),
-			Radius: uint32(GRASS_MIN_RADIUS + rand.Float64()*(GRASS_MAX_RADIUS-GRASS_MIN_RADIUS)),
-		})
-	}
-
-	return &Map
+	// queues what's sent to Conn, nil without one
+	outbox *outbox
+	// magazines of the weapons the player isn't holding
+	holstered map[string]weaponAmmo
+	lastShot  time.Time
+	// bumped to cancel a pending reload
+	reloadGeneration uint64
+	// set for server-controlled players, which have no Conn
+	bot *botBrain
+	// players whose position this player's client was last sent
+	seen [6]bool
+	// set once the player is eliminated and their connection only watches
+	spectator *Spectator
+	mu        sync.RWMutex
 }
 
-func calculateNewPosition(currentPosition *pb.Position, angle *float64, speed float64, newPosition *pb.Position) {
-	newPosition.X = currentPosition.X + math.Cos(*angle)*speed
-	newPosition.Y = currentPosition.Y + math.Sin(*angle)*speed
+// connect attaches the player's socket.
+func (player *Player) connect(conn *net.Conn) {
+	player.mu.Lock()
+	defer player.mu.Unlock()
+	player.Conn = conn
+	player.outbox = newOutbox(*conn)
 }
 
-func normalizeMovement(movement *pb.Position) float64 {
-	var magnitude = math.Sqrt(movement.X*movement.X + movement.Y*movement.Y)
-	if magnitude == 0 {
-		return math.Atan2(movement.Y, movement.X)
-	}
-	return math.Atan2(movement.Y/magnitude, movement.X/magnitude)
-}
-
-func checkInGrass(position *pb.Position, inGrass *bool, wg *sync.WaitGroup) {
-	defer wg.Done()
-	for _, grass := range gameMap.GrassPatches {
-		if math.Hypot(position.X-float64(grass.X), position.Y-float64(grass.Y)) < float64(grass.Radius) {
-			*inGrass = true
-			return
-		}
-	}
-	*inGrass = false
-}
-
-func checkCollision(size float64, position *pb.Position, isCollided *bool, wg *sync.WaitGroup) {
-	defer wg.Done()
-
-	if position.X-size < 0 ||
-		position.X+size > float64(MAP_WIDTH) ||
-		position.Y-size < 0 ||
-		position.Y+size > float64(MAP_HEIGHT) {
-		*isCollided = true
-		return
-	}
-
-	for _, obstacle := range gameMap.Obstacles {
-		if position.X+size > float64(obstacle.X) &&
-			position.X-size < float64((obstacle.X)+uint32(obstacle.Width)) &&
-			position.Y+size > float64(obstacle.Y) &&
-			position.Y-size < float64((obstacle.Y)+uint32(obstacle.Height)) {
-			*isCollided = true
-			return
-		}
+// send queues a frame for the player's client, if they have one. The caller
+// holds player.mu.
+func (player *Player) send(event string, data []byte) {
+	if player.outbox != nil {
+		player.outbox.send(event, data)
 	}
-	*isCollided = false
 }
 
+// handleBulletMovement moves a bullet every tick until it hits something or
+// runs out of range. Each step is swept from the old position to the new
+// one, so fast bullets can't pass through thin obstacles or players, and the
+// bullet stops at the earliest hit.
 func (room *Room) handleBulletMovement(bullet *pb.Bullet, playerID *int32) {
-	var wg sync.WaitGroup
-	var isCollided bool
-	var newPosition pb.Position
+	var weapon = sim.WeaponByName(bullet.Weapon)
+	var travelled float64
+	var hit = func(from, to *pb.Position, limit float64) (float64, bool) {
+		return checkBulletHit(room, bullet, playerID, from, to, limit)
+	}
+	metrics.bulletGoroutines.Add(1)
+	defer metrics.bulletGoroutines.Add(-1)
 	for {
-		calculateNewPosition(bullet.Position, &bullet.Rotation, BULLET_SPEED, &newPosition)
-		wg.Add(2)
-		go checkCollision(BULLET_SIZE, &newPosition, &isCollided, &wg)
-		go checkBulletHit(room, bullet, playerID, &wg)
-		wg.Wait()
-		if isCollided || bullet.Expired {
-			bullet.Expired = true
-		} else {
-			bullet.Position = &newPosition
-		}
+		var start = time.Now()
+		bullet.Position, travelled, bullet.Expired = sim.MoveBullet(room.obstacles, weapon, bullet.Position, bullet.Rotation, travelled, hit)
+		metrics.bulletStep.since(start)
 		if room == nil {
 			return
 		}
@@ -129,50 +83,84 @@ func (room *Room) handleBulletMovement(bullet *pb.Bullet, playerID *int32) {
 		if roomSize <= 1 {
 			return
 		}
-		var ID int32 = 255
-		go room.broadcastParallel(&pb.Message{
+		var ID int32 = BULLET_ID
+		var update = &pb.Message{
 			Id:      &ID,
 			Event:   SHOOT,
-			Payload: &pb.Payload{Bullet: bullet},
-		})
+			Payload: &pb.Payload{Bullet: proto.Clone(bullet).(*pb.Bullet)},
+		}
+		if travelled <= SHOT_REVEAL_RANGE {
+			room.mu.RLock()
+			room.broadcastSighted(*playerID, update, nil)
+			room.mu.RUnlock()
+		} else {
+			go room.broadcastParallel(update)
+		}
 		if bullet.Expired {
 			return
 		}
-		time.Sleep(16 * time.Millisecond)
+		time.Sleep(sim.TICK)
 	}
 }
 
-func checkBulletHit(room *Room, bullet *pb.Bullet, playerID *int32, wg *sync.WaitGroup) {
+// checkBulletHit sweeps a bullet from from to to and damages the first
+// player it meets before limit, the fraction of the way where it hits an
+// obstacle. It returns how far along the hit is.
+func checkBulletHit(room *Room, bullet *pb.Bullet, playerID *int32, from, to *pb.Position, limit float64) (float64, bool) {
 	room.mu.RLock()
 	defer room.mu.RUnlock()
-	defer wg.Done()
 
-	for _, player := range room.player {
-		if player != nil && *playerID != player.Id {
-			player.mu.Lock()
-			if math.Hypot(player.Position.X-bullet.Position.X, player.Position.Y-bullet.Position.Y) < PLAYER_SIZE {
-				bullet.Expired = true
-				player.Health -= 10
-				if player.Health == 0 {
-					room.broadcast <- &pb.Message{
-						Id:    &player.Id,
-						Event: KICK,
-					}
-					room.broadcast <- &pb.Message{
-						Id:    playerID,
-						Event: KILLS,
-					}
-				} else {
-					go room.broadcastParallel(&pb.Message{
-						Id:      &player.Id,
-						Event:   HIT,
-						Payload: &pb.Payload{Health: &player.Health},
-					})
+	var shooterTeam int32 = sim.TEAM_NONE
+	var multiplier int32 = 1
+	if shooter := room.player[*playerID]; shooter != nil {
+		shooter.mu.RLock()
+		shooterTeam = shooter.Team
+		multiplier = shooter.damageMultiplier(time.Now())
+		shooter.mu.RUnlock()
+	}
+
+	var target *Player
+	var first = limit
+	for _, ID := range room.index.near(from, to, sim.PLAYER_SIZE) {
+		if player := room.player[ID]; player != nil && *playerID != player.Id {
+			player.mu.RLock()
+			if !player.IsDead && !room.isFriendlyFire(shooterTeam, player.Team) {
+				if t, ok := sim.SegmentCircle(from, to, player.Position.X, player.Position.Y, sim.PLAYER_SIZE); ok && t <= first {
+					target, first = player, t
 				}
-				player.mu.Unlock()
-				return
 			}
-			player.mu.Unlock()
+			player.mu.RUnlock()
 		}
 	}
+	if target == nil {
+		return 0, false
+	}
+
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	// the target may have died to another bullet in the meantime
+	if target.IsDead {
+		return 0, false
+	}
+	room.damagePlayer(target, sim.WeaponByName(bullet.Weapon).Damage*multiplier, playerID, shooterTeam)
+	return first, true
+}
+
+// damagePlayer takes amount off a player's shield and then their health, and
+// either reports the hit or hands the player to handleDeath. attackerID is
+// nil for damage nobody dealt, like the safe zone's, and attackerTeam is
+// the team the attacker had when it was dealt. The caller holds room.mu and
+// player.mu.
+func (room *Room) damagePlayer(player *Player, amount int32, attackerID *int32, attackerTeam int32) {
+	player.Health, player.Shield = sim.ApplyDamage(player.Health, player.Shield, amount)
+	if player.Health <= 0 {
+		room.handleDeath(player, attackerID, attackerTeam)
+		return
+	}
+	var health, shield = player.Health, player.Shield
+	go room.broadcastParallel(&pb.Message{
+		Id:      &player.Id,
+		Event:   HIT,
+		Payload: &pb.Payload{Health: &health, Shield: &shield},
+	})
 }
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+const (
+	ZONE_RADIUS          = 150
+	ZONE_TICK            = time.Second
+	ZONE_MOVE_INTERVAL   = 45 * time.Second
+	ZONE_POINTS_PER_TICK = 1
+	DEFAULT_SCORE_LIMIT  = 100
+)
+
+// kothState tracks the control zone and, with teams, the team points. Like
+// ctfState, its lock is taken after room.mu and player.mu, never before.
+type kothState struct {
+	zones      []*pb.ControlZone
+	current    int
+	position   *pb.Position
+	radius     uint32
+	movesAt    time.Time
+	teamPoints [sim.TEAM_COUNT]int32
+	mu         sync.Mutex
+}
+
+func (room *Room) isKingOfTheHill() bool {
+	return room.settings.Mode == sim.MODE_KING_OF_THE_HILL
+}
+
+// newKOTHState starts on the map's first control zone, or a zone centered on
+// the map when it doesn't define any.
+func newKOTHState(gameMap *pb.GameMap) *kothState {
+	var state = kothState{zones: gameMap.ControlZones}
+	if len(state.zones) == 0 {
+		state.position = &pb.Position{X: float64(gameMap.Width) / 2, Y: float64(gameMap.Height) / 2}
+		state.radius = ZONE_RADIUS
+	} else {
+		state.position = state.zones[0].Position
+		state.radius = state.zones[0].Radius
+	}
+	return &state
+}
+
+// moveZone switches to the next map zone, or to a random spot a player can
+// stand on when the map has fewer than two zones. The caller holds state.mu.
+func (state *kothState) moveZone(gameMap *pb.GameMap, obstacles *sim.ObstacleIndex) {
+	if len(state.zones) >= 2 {
+		state.current = (state.current + 1) % len(state.zones)
+		state.position = state.zones[state.current].Position
+		state.radius = state.zones[state.current].Radius
+		return
+	}
+	for i := 0; i < sim.SPAWN_SAMPLES; i++ {
+		candidate := &pb.Position{
+			X: float64(state.radius) + rand.Float64()*(float64(gameMap.Width)-2*float64(state.radius)),
+			Y: float64(state.radius) + rand.Float64()*(float64(gameMap.Height)-2*float64(state.radius)),
+		}
+		if !obstacles.IsBlocked(sim.PLAYER_SIZE, candidate) {
+			state.position = candidate
+			return
+		}
+	}
+}
+
+// runControlZone awards points every ZONE_TICK to the only player, or team,
+// standing in the zone and moves the zone every ZONE_MOVE_INTERVAL.
+func (room *Room) runControlZone() {
+	var state = room.koth
+	state.mu.Lock()
+	state.movesAt = time.Now().Add(ZONE_MOVE_INTERVAL)
+	state.mu.Unlock()
+
+	ticker := time.NewTicker(ZONE_TICK)
+	defer ticker.Stop()
+	for range ticker.C {
+		room.mu.RLock()
+		if room.isOver {
+			room.mu.RUnlock()
+			return
+		}
+		room.tickControlZone()
+		room.mu.RUnlock()
+	}
+}
+
+// tickControlZone runs one scoring step. The caller holds room.mu.
+func (room *Room) tickControlZone() {
+	var state = room.koth
+
+	state.mu.Lock()
+	if time.Now().After(state.movesAt) {
+		state.moveZone(room.gameMap, room.obstacles)
+		state.movesAt = time.Now().Add(ZONE_MOVE_INTERVAL)
+		room.logger().Info("control zone moved", "x", state.position.X, "y", state.position.Y)
+	}
+	var center, radius = state.position, state.radius
+	state.mu.Unlock()
+
+	// sides are player IDs, or teams when teams are on
+	var sides = map[int32][]*Player{}
+	for _, player := range room.player {
+		if player == nil {
+			continue
+		}
+		player.mu.RLock()
+		if !player.IsDead && sim.InsideCircle(player.Position, center.X, center.Y, float64(radius)) {
+			side := player.Id
+			if room.settings.Teams {
+				side = player.Team
+			}
+			if !room.settings.Teams || sim.IsValidTeam(side) {
+				sides[side] = append(sides[side], player)
+			}
+		}
+		player.mu.RUnlock()
+	}
+
+	var zone = pb.ControlZone{Position: center, Radius: radius, Contested: len(sides) > 1}
+	var points int32
+	var owners []int32
+	if len(sides) == 1 {
+		for side, players := range sides {
+			var owner = side
+			zone.Owner = &owner
+			for _, player := range players {
+				owners = append(owners, player.Id)
+			}
+			if room.settings.Teams {
+				state.mu.Lock()
+				state.teamPoints[side-1] += ZONE_POINTS_PER_TICK
+				points = state.teamPoints[side-1]
+				state.mu.Unlock()
+			} else {
+				players[0].mu.Lock()
+				players[0].Score += ZONE_POINTS_PER_TICK
+				points = players[0].Score
+				players[0].mu.Unlock()
+			}
+		}
+	}
+
+	state.mu.Lock()
+	zone.NextMove = uint32(time.Until(state.movesAt).Seconds())
+	state.mu.Unlock()
+
+	var update = pb.Message{
+		Event:   ZONE,
+		Payload: &pb.Payload{Zone: &zone, Players: []*pb.Player{}},
+	}
+	for _, player := range room.player {
+		if player != nil {
+			player.mu.RLock()
+			update.Payload.Players = append(update.Payload.Players, &pb.Player{Id: player.Id, Score: player.Score})
+			player.mu.RUnlock()
+		}
+	}
+	if room.settings.Teams {
+		update.Payload.TeamScores = room.teamScores()
+	}
+	if owners == nil {
+		go room.broadcastParallel(&update)
+	} else {
+		// the owner is only named to the clients who can see someone holding
+		// the zone
+		var hidden = proto.Clone(&update).(*pb.Message)
+		hidden.Payload.Zone.Owner = nil
+		room.sight.Lock()
+		room.sendSighted(owners, &update, hidden)
+		room.sight.Unlock()
+	}
+
+	if points >= room.settings.ScoreLimit {
+		room.logger().Info("score limit reached", "owner", *zone.Owner)
+		go room.broadcastGameOver()
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// newLogger builds the process logger from LOG_LEVEL (debug, info, warn,
+// error) and LOG_FORMAT (text or json).
+func newLogger(w io.Writer) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(os.Getenv("LOG_LEVEL"))); err != nil {
+		level = slog.LevelInfo
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	if strings.EqualFold(os.Getenv("LOG_FORMAT"), "json") {
+		return slog.New(slog.NewJSONHandler(w, options))
+	}
+	return slog.New(slog.NewTextHandler(w, options))
+}
+
+func (room *Room) logger() *slog.Logger {
+	return slog.With("room", room.ID)
+}
+
+func (room *Room) playerLogger(ID int32) *slog.Logger {
+	return slog.With("room", room.ID, "player", ID)
+}
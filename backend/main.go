@@ -2,27 +2,70 @@ package main
 
 import (
 	pb "battle-arena/message"
+	"battle-arena/sim"
 	"encoding/json"
+	"log/slog"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/gobwas/ws"
 )
 
 func main() {
+	slog.SetDefault(newLogger(os.Stderr))
+
 	mux := http.NewServeMux()
 
+	loadMaps(mapsDir())
+
+	mux.HandleFunc("GET /api/maps", listMaps)
 	mux.HandleFunc("POST /api/rooms/create", createRoom)
 	mux.HandleFunc("POST /api/rooms/join", joinRoom)
 	mux.HandleFunc("GET /play", playGame)
+	mux.HandleFunc("GET /api/replays", listReplays)
+	mux.HandleFunc("GET /api/replays/{name}", downloadReplay)
+	mux.HandleFunc("GET /metrics", serveMetrics)
+	registerAdminRoutes(mux)
 
 	handler := enableCORS(mux)
 
-	http.ListenAndServe(":8080", handler)
+	addr := os.Getenv("ADDR")
+	if addr == "" {
+		addr = ":8080"
+	}
+
+	tlsConfig, err := loadTLSConfig()
+	if err != nil {
+		slog.Error("failed to load TLS config", "err", err)
+		os.Exit(1)
+	}
+
+	server := &http.Server{
+		Addr:      addr,
+		Handler:   handler,
+		TLSConfig: tlsConfig,
+	}
+
+	slog.Info("server listening", "addr", addr, "tls", tlsConfig != nil)
+
+	// HTTP/2 is negotiated automatically over TLS; WebSocket clients still
+	// upgrade over HTTP/1.1 on /play.
+	if tlsConfig != nil {
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
+	slog.Error("server stopped", "err", err)
+	os.Exit(1)
 }
 
 func enableCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "battle-arena.akashgupta.tech")
+		w.Header().Add("Vary", "Origin")
+		if origin := r.Header.Get("Origin"); allowedOrigins.allows(origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 		if r.Method == "OPTIONS" {
@@ -42,21 +85,69 @@ func createRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	gameMap, err := newRoomMap(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	settings, err := parseGameSettings(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	var roodId = ROOM_ID
 	ROOM_ID++
 
+	var ctf *ctfState
+	if settings.Mode == sim.MODE_CAPTURE_THE_FLAG {
+		if ctf = newCTFState(gameMap); ctf == nil {
+			http.Error(w, "Map has no flag bases", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var koth *kothState
+	if settings.Mode == sim.MODE_KING_OF_THE_HILL {
+		koth = newKOTHState(gameMap)
+	}
+
+	var obstacles = sim.NewObstacleIndex(gameMap)
+	var safeZone *safeZoneState
+	if settings.SafeZone {
+		safeZone = newSafeZoneState(gameMap, obstacles)
+	}
+
 	room := &Room{
 		player:        [6]*Player{},
+		gameMap:       gameMap,
+		settings:      settings,
+		ctf:           ctf,
+		koth:          koth,
+		safeZone:      safeZone,
+		pickups:       newPickupState(gameMap),
+		index:         newPlayerIndex(gameMap),
+		obstacles:     obstacles,
+		nav:           newNavGrid(gameMap, obstacles),
+		recorder:      newRecorder(replaysDir()),
 		broadcast:     make(chan *pb.Message),
+		done:          make(chan struct{}),
 		ID:            roodId,
 		IsGameStarted: false,
+		CreatedAt:     time.Now(),
 	}
 
 	var playerID int32 = 0
-	initializePlayer(&player, playerID)
+	initializePlayer(&player, playerID, room.spawnPosition(playerID))
+	if settings.Teams {
+		player.Team = room.balancedTeam(playerID)
+	}
 
 	room.player[playerID] = &player
 	rooms.Store(roodId, room)
+	room.logger().Info("room created", "map", gameMap.Name, "seed", gameMap.Seed, "mode", settings.Mode)
+	room.playerLogger(playerID).Info("player joined", "name", player.Name)
 
 	go room.run()
 
@@ -87,6 +178,7 @@ func joinRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	room.(*Room).mu.Lock()
 	var playerID *int32 = nil
 	for i, p := range room.(*Room).player {
 		if p == nil {
@@ -97,32 +189,60 @@ func joinRoom(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if playerID == nil {
+		room.(*Room).mu.Unlock()
 		http.Error(w, "Room is full", http.StatusBadRequest)
 		return
 	}
 
-	initializePlayer(&player, *playerID)
+	initializePlayer(&player, *playerID, room.(*Room).spawnPosition(*playerID))
+	if room.(*Room).settings.Teams {
+		player.Team = room.(*Room).balancedTeam(*playerID)
+	}
 
 	room.(*Room).player[*playerID] = &player
+	room.(*Room).mu.Unlock()
+	room.(*Room).playerLogger(*playerID).Info("player joined", "name", player.Name)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]int32{"playerId": player.Id})
 }
 
-func initializePlayer(player *Player, id int32) {
+func initializePlayer(player *Player, id int32, spawn *pb.Position) {
 	player.Id = id
-	player.Health = 100
+	player.Health = sim.PLAYER_MAX_HEALTH
 	player.IsReady = false
 	player.Kills = 0
 	player.Rotation = 0
-	player.Position = &pb.Position{
-		X: float64(MAP_WIDTH/uint16(player.Id+1) - 200),
-		Y: float64(MAP_HEIGHT/uint16(player.Id+1) - 200),
+	player.Position = spawn
+	resetWeapons(player)
+}
+
+func mapsDir() string {
+	if dir := os.Getenv("MAPS_DIR"); dir != "" {
+		return dir
 	}
+	return "maps"
 }
 
 func playGame(w http.ResponseWriter, r *http.Request) {
+	// Browsers always send Origin on WebSocket handshakes, so a foreign page
+	// can't open a game socket on behalf of our users.
+	if origin := r.Header.Get("Origin"); origin != "" && !allowedOrigins.allows(origin) {
+		slog.Warn("rejected websocket origin", "origin", origin)
+		http.Error(w, "Origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	if name := r.URL.Query().Get("replay"); name != "" {
+		watchReplay(w, r, name)
+		return
+	}
+	if r.URL.Query().Has("spectate") {
+		spectateRoom(w, r)
+		return
+	}
+
 	playerId, roomId, err := parseParams(r)
 	if err != nil {
 		http.Error(w, "Invalid Inputs", http.StatusBadRequest)
@@ -135,8 +255,21 @@ func playGame(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if playerId < 0 || int(playerId) >= len(room.(*Room).player) {
+		http.Error(w, "Invalid Player Id", http.StatusBadRequest)
+		return
+	}
+	room.(*Room).mu.RLock()
+	var slot = room.(*Room).player[playerId]
+	room.(*Room).mu.RUnlock()
+	if slot == nil || slot.isBot() {
+		http.Error(w, "Invalid Player Id", http.StatusBadRequest)
+		return
+	}
+
 	conn, _, _, err := ws.UpgradeHTTP(r, w)
 	if err != nil {
+		room.(*Room).playerLogger(playerId).Error("websocket upgrade failed", "err", err)
 		http.Error(w, "Failed to connect", http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"net/url"
+	"strconv"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+// Symmetry modes for generated maps
+const (
+	SYMMETRY_NONE       = "none"
+	SYMMETRY_HORIZONTAL = "horizontal" // mirrored left to right
+	SYMMETRY_VERTICAL   = "vertical"   // mirrored top to bottom
+	SYMMETRY_QUAD       = "quad"       // mirrored on both axes
+	SYMMETRY_ROTATIONAL = "rotational" // 180 degree rotation around the center
+)
+
+const (
+	OBSTACLE_MIN_SIZE        = 60
+	OBSTACLE_MAX_SIZE        = 240
+	CIRCLE_OBSTACLE_CHANCE   = 0.25
+	DEFAULT_OBSTACLE_DENSITY = 0.06
+	DEFAULT_GRASS_DENSITY    = 0.035
+	MAX_OBSTACLE_DENSITY     = 0.3
+	MAX_GRASS_DENSITY        = 0.2
+	MIN_GENERATED_SIZE       = 800
+	MAX_GENERATED_SIZE       = 4000
+	NAV_CELL_SIZE            = sim.PLAYER_SIZE
+	MAX_PLACEMENT_ATTEMPTS   = 50
+	SPAWN_POINT_COUNT        = 6
+	FLAG_SPAWN_CLEARANCE     = 150
+)
+
+func defaultMapParams() *pb.MapParams {
+	return &pb.MapParams{
+		Width:           MAP_WIDTH,
+		Height:          MAP_HEIGHT,
+		ObstacleDensity: DEFAULT_OBSTACLE_DENSITY,
+		GrassDensity:    DEFAULT_GRASS_DENSITY,
+		Symmetry:        SYMMETRY_NONE,
+	}
+}
+
+// parseMapParams reads the optional generator parameters from a room
+// creation query: seed, width, height, obstacles, grass and symmetry.
+func parseMapParams(query url.Values) (int64, *pb.MapParams, error) {
+	var params = defaultMapParams()
+	var seed = rand.Int63()
+	var err error
+
+	if value := query.Get("seed"); value != "" {
+		if seed, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return 0, nil, fmt.Errorf("invalid seed: %w", err)
+		}
+	}
+	for key, target := range map[string]*uint32{"width": &params.Width, "height": &params.Height} {
+		if value := query.Get(key); value != "" {
+			size, err := strconv.ParseUint(value, 10, 32)
+			if err != nil || size < MIN_GENERATED_SIZE || size > MAX_GENERATED_SIZE {
+				return 0, nil, fmt.Errorf("%s must be between %d and %d", key, MIN_GENERATED_SIZE, MAX_GENERATED_SIZE)
+			}
+			*target = uint32(size)
+		}
+	}
+	for key, limit := range map[string]float64{"obstacles": MAX_OBSTACLE_DENSITY, "grass": MAX_GRASS_DENSITY} {
+		if value := query.Get(key); value != "" {
+			density, err := strconv.ParseFloat(value, 64)
+			if err != nil || density < 0 || density > limit {
+				return 0, nil, fmt.Errorf("%s density must be between 0 and %g", key, limit)
+			}
+			if key == "obstacles" {
+				params.ObstacleDensity = density
+			} else {
+				params.GrassDensity = density
+			}
+		}
+	}
+	if value := query.Get("symmetry"); value != "" {
+		switch value {
+		case SYMMETRY_NONE, SYMMETRY_HORIZONTAL, SYMMETRY_VERTICAL, SYMMETRY_QUAD, SYMMETRY_ROTATIONAL:
+			params.Symmetry = value
+		default:
+			return 0, nil, fmt.Errorf("unknown symmetry %q", value)
+		}
+	}
+	return seed, params, nil
+}
+
+// generateMap builds a map from a seed and parameters. The same seed and
+// parameters always produce the same map. Every walkable cell is reachable
+// from every other one, and no grass patch or spawn point overlaps an
+// obstacle.
+func generateMap(seed int64, params *pb.MapParams) *pb.GameMap {
+	var rng = rand.New(rand.NewSource(seed))
+	var Map = pb.GameMap{
+		Name:   DEFAULT_MAP,
+		Width:  params.Width,
+		Height: params.Height,
+		Seed:   seed,
+		Params: params,
+	}
+
+	placeObstacles(rng, &Map, params)
+	placeGrass(rng, &Map, params)
+	placeSpawnPoints(rng, &Map)
+	placeFlagBases(&Map)
+	placePickupSpawns(&Map)
+
+	return &Map
+}
+
+// obstacleGroup is an obstacle and its mirror images, which are kept or
+// dropped together so the map stays symmetric.
+type obstacleGroup struct {
+	rects   []*pb.Obstacle
+	circles []*pb.CircleObstacle
+}
+
+// placeObstacles covers the map up to its obstacle density, then drops the
+// obstacles that cut part of it off.
+func placeObstacles(rng *rand.Rand, Map *pb.GameMap, params *pb.MapParams) {
+	var area = float64(Map.Width) * float64(Map.Height)
+	var target = area * params.ObstacleDensity
+	var covered float64
+	var groups []*obstacleGroup
+
+	for attempts := 0; covered < target && attempts < MAX_PLACEMENT_ATTEMPTS*10; attempts++ {
+		var rects []*pb.Obstacle
+		var circles []*pb.CircleObstacle
+
+		if rng.Float64() < CIRCLE_OBSTACLE_CHANCE {
+			radius := uint32(OBSTACLE_MIN_SIZE/2 + rng.Intn((OBSTACLE_MAX_SIZE-OBSTACLE_MIN_SIZE)/4))
+			circle := &pb.CircleObstacle{
+				X:      radius + uint32(rng.Intn(int(Map.Width-2*radius))),
+				Y:      radius + uint32(rng.Intn(int(Map.Height-2*radius))),
+				Radius: radius,
+			}
+			for _, mirrored := range mirrorRect(Map, params.Symmetry, circle.X, circle.Y, 0, 0) {
+				circles = append(circles, &pb.CircleObstacle{X: mirrored[0], Y: mirrored[1], Radius: radius})
+			}
+		} else {
+			width := uint32(OBSTACLE_MIN_SIZE + rng.Intn(OBSTACLE_MAX_SIZE-OBSTACLE_MIN_SIZE))
+			height := uint32(OBSTACLE_MIN_SIZE + rng.Intn(OBSTACLE_MAX_SIZE-OBSTACLE_MIN_SIZE))
+			x := uint32(rng.Intn(int(Map.Width - width)))
+			y := uint32(rng.Intn(int(Map.Height - height)))
+			for _, mirrored := range mirrorRect(Map, params.Symmetry, x, y, width, height) {
+				rects = append(rects, &pb.Obstacle{X: mirrored[0], Y: mirrored[1], Width: width, Height: height})
+			}
+		}
+
+		groups = append(groups, &obstacleGroup{rects: rects, circles: circles})
+		for _, obstacle := range rects {
+			covered += float64(obstacle.Width) * float64(obstacle.Height)
+		}
+		for _, circle := range circles {
+			covered += math.Pi * float64(circle.Radius) * float64(circle.Radius)
+		}
+	}
+	connectMap(Map, groups)
+}
+
+// connectMap sets the map's obstacles to the groups that leave every
+// walkable cell reachable. While some cells are cut off from the largest
+// walkable region, every group next to them is dropped; each round drops at
+// least one, so the grid is flooded a handful of times rather than once per
+// obstacle.
+func connectMap(Map *pb.GameMap, groups []*obstacleGroup) {
+	for {
+		Map.Obstacles, Map.CircleObstacles = nil, nil
+		for _, group := range groups {
+			Map.Obstacles = append(Map.Obstacles, group.rects...)
+			Map.CircleObstacles = append(Map.CircleObstacles, group.circles...)
+		}
+		var edges = cutOffEdges(newNavGrid(Map, sim.NewObstacleIndex(Map)))
+		if len(edges) == 0 {
+			return
+		}
+
+		var kept []*obstacleGroup
+		for _, group := range groups {
+			if !group.borders(edges) {
+				kept = append(kept, group)
+			}
+		}
+		if len(kept) == len(groups) {
+			return
+		}
+		groups = kept
+	}
+}
+
+// borders reports whether any of the group's obstacles is close enough to
+// one of the cell centers to block a cell next to it.
+func (group *obstacleGroup) borders(centers []*pb.Position) bool {
+	var reach = math.Sqrt2*NAV_CELL_SIZE + sim.PLAYER_SIZE
+	for _, center := range centers {
+		for _, obstacle := range group.rects {
+			if sim.OverlapsObstacle(obstacle, reach, center) {
+				return true
+			}
+		}
+		for _, circle := range group.circles {
+			if sim.OverlapsCircle(circle, reach, center) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mirrorRect returns the top-left corner of a width x height rectangle at
+// (x, y) followed by the corners of its mirror images for the symmetry mode.
+// Points are rectangles with no size. Copies that land on the original are
+// dropped.
+func mirrorRect(Map *pb.GameMap, symmetry string, x, y, width, height uint32) [][2]uint32 {
+	var mirrorX, mirrorY = Map.Width - x - width, Map.Height - y - height
+	var points = [][2]uint32{{x, y}}
+
+	switch symmetry {
+	case SYMMETRY_HORIZONTAL:
+		points = append(points, [2]uint32{mirrorX, y})
+	case SYMMETRY_VERTICAL:
+		points = append(points, [2]uint32{x, mirrorY})
+	case SYMMETRY_QUAD:
+		points = append(points, [2]uint32{mirrorX, y}, [2]uint32{x, mirrorY}, [2]uint32{mirrorX, mirrorY})
+	case SYMMETRY_ROTATIONAL:
+		points = append(points, [2]uint32{mirrorX, mirrorY})
+	}
+
+	var unique [][2]uint32
+	for _, point := range points {
+		var duplicate bool
+		for _, seen := range unique {
+			if seen == point {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			unique = append(unique, point)
+		}
+	}
+	return unique
+}
+
+func placeGrass(rng *rand.Rand, Map *pb.GameMap, params *pb.MapParams) {
+	var area = float64(Map.Width) * float64(Map.Height)
+	var meanRadius = float64(GRASS_MIN_RADIUS+GRASS_MAX_RADIUS) / 2
+	var count = int(area * params.GrassDensity / (math.Pi * meanRadius * meanRadius))
+	var obstacles = sim.NewObstacleIndex(Map)
+
+	for placed, attempts := 0, 0; placed < count && attempts < count*MAX_PLACEMENT_ATTEMPTS; attempts++ {
+		radius := uint32(GRASS_MIN_RADIUS + rng.Float64()*(GRASS_MAX_RADIUS-GRASS_MIN_RADIUS))
+		x := radius + uint32(rng.Intn(int(Map.Width-2*radius)))
+		y := radius + uint32(rng.Intn(int(Map.Height-2*radius)))
+
+		var patches []*pb.GrassPatch
+		var blocked bool
+		for _, mirrored := range mirrorRect(Map, params.Symmetry, x, y, 0, 0) {
+			patch := &pb.GrassPatch{X: mirrored[0], Y: mirrored[1], Radius: radius}
+			if obstacles.IsBlocked(float64(radius), &pb.Position{X: float64(patch.X), Y: float64(patch.Y)}) {
+				blocked = true
+				break
+			}
+			patches = append(patches, patch)
+		}
+		if blocked {
+			continue
+		}
+		Map.GrassPatches = append(Map.GrassPatches, patches...)
+		placed += len(patches)
+	}
+}
+
+// placeSpawnPoints spreads spawn points over the walkable area by picking
+// the candidate farthest from the points chosen so far.
+func placeSpawnPoints(rng *rand.Rand, Map *pb.GameMap) {
+	var cells = walkableCells(Map)
+	if len(cells) == 0 {
+		return
+	}
+
+	var first = cells[rng.Intn(len(cells))]
+	Map.SpawnPoints = append(Map.SpawnPoints, &pb.Position{X: first[0], Y: first[1]})
+
+	for len(Map.SpawnPoints) < SPAWN_POINT_COUNT {
+		best := farthestCell(cells, Map.SpawnPoints)
+		Map.SpawnPoints = append(Map.SpawnPoints, &pb.Position{X: best[0], Y: best[1]})
+	}
+}
+
+// farthestCell returns the cell whose nearest point is the farthest away.
+func farthestCell(cells [][2]float64, points []*pb.Position) [2]float64 {
+	var best [2]float64
+	var bestDistance = -1.0
+	for _, cell := range cells {
+		var nearest = math.Inf(1)
+		for _, point := range points {
+			nearest = math.Min(nearest, math.Hypot(cell[0]-point.X, cell[1]-point.Y))
+		}
+		if nearest > bestDistance {
+			best, bestDistance = cell, nearest
+		}
+	}
+	return best
+}
+
+// placePickupSpawns carries on the farthest-point sampling of the spawn
+// points and flag bases, so pickups sit in the open ground between them.
+func placePickupSpawns(Map *pb.GameMap) {
+	var cells = walkableCells(Map)
+	if len(cells) == 0 {
+		return
+	}
+
+	var taken = append([]*pb.Position{}, Map.SpawnPoints...)
+	for _, base := range Map.FlagBases {
+		taken = append(taken, base.Position)
+	}
+	for i := 0; i < PICKUP_SPAWN_COUNT; i++ {
+		best := farthestCell(cells, taken)
+		position := &pb.Position{X: best[0], Y: best[1]}
+		taken = append(taken, position)
+		Map.PickupSpawns = append(Map.PickupSpawns, &pb.PickupSpawn{
+			Position: position,
+			Item:     PICKUP_ITEMS[i%len(PICKUP_ITEMS)],
+		})
+	}
+}
+
+// placeFlagBases puts each team's flag base near one of the first two spawn
+// points, which farthest-point sampling places far apart, but at least
+// FLAG_SPAWN_CLEARANCE from every spawn point so nobody spawns on a flag.
+// Maps too cramped for that get the free cell farthest from the spawns.
+func placeFlagBases(Map *pb.GameMap) {
+	var cells = walkableCells(Map)
+	if len(Map.SpawnPoints) < sim.TEAM_COUNT || len(cells) == 0 {
+		return
+	}
+	for team := int32(sim.TEAM_RED); team <= sim.TEAM_COUNT; team++ {
+		var anchor = Map.SpawnPoints[team-1]
+		var best [2]float64
+		var bestDistance = math.Inf(1)
+		for _, cell := range cells {
+			var clear = true
+			for _, spawn := range Map.SpawnPoints {
+				if math.Hypot(cell[0]-spawn.X, cell[1]-spawn.Y) < FLAG_SPAWN_CLEARANCE {
+					clear = false
+					break
+				}
+			}
+			if distance := math.Hypot(cell[0]-anchor.X, cell[1]-anchor.Y); clear && distance < bestDistance {
+				best, bestDistance = cell, distance
+			}
+		}
+		if math.IsInf(bestDistance, 1) {
+			best = farthestCell(cells, Map.SpawnPoints)
+		}
+		Map.FlagBases = append(Map.FlagBases, &pb.FlagBase{
+			Team:     team,
+			Position: &pb.Position{X: best[0], Y: best[1]},
+		})
+	}
+}
+
+// walkableCells returns the centers of the grid cells a player can stand in.
+func walkableCells(Map *pb.GameMap) [][2]float64 {
+	var cells [][2]float64
+	var grid = newNavGrid(Map, sim.NewObstacleIndex(Map))
+	for cell, walkable := range grid.walkable {
+		if walkable {
+			center := grid.center(cell)
+			cells = append(cells, [2]float64{center.X, center.Y})
+		}
+	}
+	return cells
+}
+
+// cutOffEdges flood-fills the walkable grid into regions and returns the
+// centers of the cells outside the largest one that border a blocked cell.
+func cutOffEdges(grid *navGrid) []*pb.Position {
+	var region = make([]int, len(grid.walkable))
+	var sizes = []int{0}
+	var largest int
+	for start, walkable := range grid.walkable {
+		if !walkable || region[start] != 0 {
+			continue
+		}
+		var label = len(sizes)
+		sizes = append(sizes, 0)
+		var stack = []int{start}
+		region[start] = label
+		for len(stack) > 0 {
+			cell := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			sizes[label]++
+
+			column, row := cell%grid.columns, cell/grid.columns
+			for _, next := range [][2]int{{column - 1, row}, {column + 1, row}, {column, row - 1}, {column, row + 1}} {
+				index := next[1]*grid.columns + next[0]
+				if grid.isWalkable(next[0], next[1]) && region[index] == 0 {
+					region[index] = label
+					stack = append(stack, index)
+				}
+			}
+		}
+		if sizes[label] > sizes[largest] {
+			largest = label
+		}
+	}
+
+	var edges []*pb.Position
+	for cell, label := range region {
+		if label == 0 || label == largest {
+			continue
+		}
+		column, row := cell%grid.columns, cell/grid.columns
+		for _, next := range [][2]int{{column - 1, row}, {column + 1, row}, {column, row - 1}, {column, row + 1}} {
+			if !grid.isWalkable(next[0], next[1]) {
+				edges = append(edges, grid.center(cell))
+				break
+			}
+		}
+	}
+	return edges
+}
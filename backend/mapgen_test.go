@@ -0,0 +1,31 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"battle-arena/sim"
+)
+
+func TestFlagBasesAwayFromSpawns(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		var gameMap = generateMap(seed, defaultMapParams())
+		if len(gameMap.FlagBases) != sim.TEAM_COUNT {
+			t.Fatalf("seed %d: %d flag bases", seed, len(gameMap.FlagBases))
+		}
+		for _, base := range gameMap.FlagBases {
+			if sim.NewObstacleIndex(gameMap).IsBlocked(sim.PLAYER_SIZE, base.Position) {
+				t.Fatalf("seed %d: base %d is blocked", seed, base.Team)
+			}
+			for i, spawn := range gameMap.SpawnPoints {
+				if math.Hypot(base.Position.X-spawn.X, base.Position.Y-spawn.Y) < FLAG_SPAWN_CLEARANCE {
+					t.Fatalf("seed %d: base %d is next to spawn point %d", seed, base.Team, i)
+				}
+			}
+		}
+		var red, blue = gameMap.FlagBases[0].Position, gameMap.FlagBases[1].Position
+		if math.Hypot(red.X-blue.X, red.Y-blue.Y) < float64(MAP_HEIGHT)/2 {
+			t.Fatalf("seed %d: bases are only %v and %v", seed, red, blue)
+		}
+	}
+}
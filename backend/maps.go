@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+// DEFAULT_MAP is the procedurally generated map; any other name refers to a
+// map loaded from MAPS_DIR.
+const DEFAULT_MAP = "procedural"
+
+// MapDefinition is the on-disk JSON format of a map. Coordinates are in
+// world units with the origin at the top-left corner.
+type MapDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Author      string `json:"author"`
+	Width       uint32 `json:"width"`
+	Height      uint32 `json:"height"`
+	Obstacles   []struct {
+		X      uint32 `json:"x"`
+		Y      uint32 `json:"y"`
+		Width  uint32 `json:"width"`
+		Height uint32 `json:"height"`
+	} `json:"obstacles"`
+	CircleObstacles []struct {
+		X      uint32 `json:"x"`
+		Y      uint32 `json:"y"`
+		Radius uint32 `json:"radius"`
+	} `json:"circleObstacles"`
+	GrassPatches []struct {
+		X      uint32 `json:"x"`
+		Y      uint32 `json:"y"`
+		Radius uint32 `json:"radius"`
+	} `json:"grassPatches"`
+	SpawnPoints []struct {
+		X float64 `json:"x"`
+		Y float64 `json:"y"`
+	} `json:"spawnPoints"`
+	FlagBases []struct {
+		Team int32   `json:"team"`
+		X    float64 `json:"x"`
+		Y    float64 `json:"y"`
+	} `json:"flagBases"`
+	ControlZones []struct {
+		X      float64 `json:"x"`
+		Y      float64 `json:"y"`
+		Radius uint32  `json:"radius"`
+	} `json:"controlZones"`
+	PickupSpawns []struct {
+		X    float64 `json:"x"`
+		Y    float64 `json:"y"`
+		Item string  `json:"item"`
+	} `json:"pickups"`
+}
+
+// GLOBAL MAPS loaded from MAPS_DIR at startup, keyed by name
+var mapRegistry = struct {
+	maps map[string]*pb.GameMap
+	mu   sync.RWMutex
+}{maps: map[string]*pb.GameMap{}}
+
+func (definition *MapDefinition) toProto() *pb.GameMap {
+	var Map = pb.GameMap{
+		Name:   definition.Name,
+		Width:  definition.Width,
+		Height: definition.Height,
+	}
+	for _, obstacle := range definition.Obstacles {
+		Map.Obstacles = append(Map.Obstacles, &pb.Obstacle{X: obstacle.X, Y: obstacle.Y, Width: obstacle.Width, Height: obstacle.Height})
+	}
+	for _, circle := range definition.CircleObstacles {
+		Map.CircleObstacles = append(Map.CircleObstacles, &pb.CircleObstacle{X: circle.X, Y: circle.Y, Radius: circle.Radius})
+	}
+	for _, grass := range definition.GrassPatches {
+		Map.GrassPatches = append(Map.GrassPatches, &pb.GrassPatch{X: grass.X, Y: grass.Y, Radius: grass.Radius})
+	}
+	for _, spawn := range definition.SpawnPoints {
+		Map.SpawnPoints = append(Map.SpawnPoints, &pb.Position{X: spawn.X, Y: spawn.Y})
+	}
+	for _, base := range definition.FlagBases {
+		Map.FlagBases = append(Map.FlagBases, &pb.FlagBase{Team: base.Team, Position: &pb.Position{X: base.X, Y: base.Y}})
+	}
+	for _, zone := range definition.ControlZones {
+		Map.ControlZones = append(Map.ControlZones, &pb.ControlZone{Position: &pb.Position{X: zone.X, Y: zone.Y}, Radius: zone.Radius})
+	}
+	for _, spawn := range definition.PickupSpawns {
+		Map.PickupSpawns = append(Map.PickupSpawns, &pb.PickupSpawn{Position: &pb.Position{X: spawn.X, Y: spawn.Y}, Item: spawn.Item})
+	}
+	return &Map
+}
+
+func validateMap(Map *pb.GameMap) error {
+	if Map.Name == "" {
+		return errors.New("map has no name")
+	}
+	if Map.Width < 4*sim.PLAYER_SIZE || Map.Height < 4*sim.PLAYER_SIZE {
+		return fmt.Errorf("map is too small: %dx%d", Map.Width, Map.Height)
+	}
+	for i, obstacle := range Map.Obstacles {
+		// summed in 64 bits so huge values can't wrap around into the map
+		if obstacle.Width == 0 || obstacle.Height == 0 ||
+			uint64(obstacle.X)+uint64(obstacle.Width) > uint64(Map.Width) ||
+			uint64(obstacle.Y)+uint64(obstacle.Height) > uint64(Map.Height) {
+			return fmt.Errorf("obstacle %d is empty or out of bounds", i)
+		}
+	}
+	for i, circle := range Map.CircleObstacles {
+		if circle.Radius == 0 || circle.X < circle.Radius || circle.Y < circle.Radius ||
+			uint64(circle.X)+uint64(circle.Radius) > uint64(Map.Width) ||
+			uint64(circle.Y)+uint64(circle.Radius) > uint64(Map.Height) {
+			return fmt.Errorf("circle obstacle %d is empty or out of bounds", i)
+		}
+	}
+	for i, grass := range Map.GrassPatches {
+		if grass.Radius == 0 || grass.X > Map.Width || grass.Y > Map.Height {
+			return fmt.Errorf("grass patch %d is empty or out of bounds", i)
+		}
+	}
+	if len(Map.SpawnPoints) == 0 {
+		return errors.New("map has no spawn points")
+	}
+	var obstacles = sim.NewObstacleIndex(Map)
+	for i, spawn := range Map.SpawnPoints {
+		if math.IsNaN(spawn.X) || math.IsNaN(spawn.Y) {
+			return fmt.Errorf("spawn point %d is not a number", i)
+		}
+		if obstacles.IsBlocked(sim.PLAYER_SIZE, spawn) {
+			return fmt.Errorf("spawn point %d is out of bounds or inside an obstacle", i)
+		}
+	}
+	for i, base := range Map.FlagBases {
+		if !sim.IsValidTeam(base.Team) {
+			return fmt.Errorf("flag base %d has an unknown team %d", i, base.Team)
+		}
+		if math.IsNaN(base.Position.X) || math.IsNaN(base.Position.Y) || obstacles.IsBlocked(sim.PLAYER_SIZE, base.Position) {
+			return fmt.Errorf("flag base %d is out of bounds or inside an obstacle", i)
+		}
+	}
+	for i, zone := range Map.ControlZones {
+		if zone.Radius == 0 || math.IsNaN(zone.Position.X) || math.IsNaN(zone.Position.Y) ||
+			zone.Position.X < 0 || zone.Position.X > float64(Map.Width) ||
+			zone.Position.Y < 0 || zone.Position.Y > float64(Map.Height) {
+			return fmt.Errorf("control zone %d is empty or out of bounds", i)
+		}
+	}
+	for i, spawn := range Map.PickupSpawns {
+		if !isValidItem(spawn.Item) {
+			return fmt.Errorf("pickup %d has an unknown item %q", i, spawn.Item)
+		}
+		if math.IsNaN(spawn.Position.X) || math.IsNaN(spawn.Position.Y) || obstacles.IsBlocked(sim.PLAYER_SIZE, spawn.Position) {
+			return fmt.Errorf("pickup %d is out of bounds or inside an obstacle", i)
+		}
+	}
+	return nil
+}
+
+func loadMapFile(path string) (*pb.GameMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var definition MapDefinition
+	if err := json.Unmarshal(data, &definition); err != nil {
+		return nil, err
+	}
+	if definition.Name == "" {
+		definition.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	Map := definition.toProto()
+	if err := validateMap(Map); err != nil {
+		return nil, err
+	}
+	return Map, nil
+}
+
+// loadMaps reads every *.json map in dir. Invalid maps are logged and
+// skipped so one bad file doesn't keep the server down.
+func loadMaps(dir string) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		slog.Error("failed to list maps", "dir", dir, "err", err)
+		return
+	}
+
+	mapRegistry.mu.Lock()
+	defer mapRegistry.mu.Unlock()
+	for _, path := range paths {
+		Map, err := loadMapFile(path)
+		if err != nil {
+			slog.Error("invalid map", "file", path, "err", err)
+			continue
+		}
+		if Map.Name == DEFAULT_MAP {
+			slog.Warn("map name is reserved for the built-in map", "file", path)
+			continue
+		}
+		mapRegistry.maps[Map.Name] = Map
+		slog.Info("loaded map", "map", Map.Name, "file", path)
+	}
+}
+
+// newRoomMap returns the map a new room will play on. Unless a map name is
+// given, every room gets its own generated map from the query's seed and
+// generator parameters.
+func newRoomMap(query url.Values) (*pb.GameMap, error) {
+	var name = query.Get("map")
+	if name == "" || name == DEFAULT_MAP {
+		seed, params, err := parseMapParams(query)
+		if err != nil {
+			return nil, err
+		}
+		return generateMap(seed, params), nil
+	}
+	mapRegistry.mu.RLock()
+	defer mapRegistry.mu.RUnlock()
+	Map, ok := mapRegistry.maps[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown map %q", name)
+	}
+	return Map, nil
+}
+
+func listMaps(w http.ResponseWriter, r *http.Request) {
+	var names = []string{DEFAULT_MAP}
+	mapRegistry.mu.RLock()
+	for name := range mapRegistry.maps {
+		names = append(names, name)
+	}
+	mapRegistry.mu.RUnlock()
+	sort.Strings(names[1:])
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]string{"maps": names})
+}
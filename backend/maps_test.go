@@ -0,0 +1,55 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	pb "battle-arena/message"
+)
+
+func TestValidateMapBounds(t *testing.T) {
+	var valid = func() *pb.GameMap {
+		return &pb.GameMap{
+			Name:        "test",
+			Width:       1000,
+			Height:      1000,
+			SpawnPoints: []*pb.Position{{X: 500, Y: 500}},
+		}
+	}
+	if _, err := loadMapFile("maps/crossroads.json"); err != nil {
+		t.Fatalf("crossroads: %v", err)
+	}
+
+	var tests = []struct {
+		name  string
+		edit  func(*pb.GameMap)
+		valid bool
+	}{
+		{"inside", func(Map *pb.GameMap) {
+			Map.Obstacles = []*pb.Obstacle{{X: 900, Y: 0, Width: 100, Height: 100}}
+			Map.CircleObstacles = []*pb.CircleObstacle{{X: 50, Y: 950, Radius: 50}}
+		}, true},
+		{"obstacle past the edge", func(Map *pb.GameMap) {
+			Map.Obstacles = []*pb.Obstacle{{X: 901, Y: 0, Width: 100, Height: 100}}
+		}, false},
+		{"obstacle wrapping around", func(Map *pb.GameMap) {
+			Map.Obstacles = []*pb.Obstacle{{X: 100, Y: 0, Width: math.MaxUint32 - 50, Height: 100}}
+		}, false},
+		{"circle over the right edge", func(Map *pb.GameMap) {
+			Map.CircleObstacles = []*pb.CircleObstacle{{X: 990, Y: 100, Radius: 20}}
+		}, false},
+		{"circle over the top edge", func(Map *pb.GameMap) {
+			Map.CircleObstacles = []*pb.CircleObstacle{{X: 100, Y: 10, Radius: 20}}
+		}, false},
+		{"circle wrapping around", func(Map *pb.GameMap) {
+			Map.CircleObstacles = []*pb.CircleObstacle{{X: 100, Y: 100, Radius: math.MaxUint32}}
+		}, false},
+	}
+	for _, test := range tests {
+		var Map = valid()
+		test.edit(Map)
+		if err := validateMap(Map); (err == nil) != test.valid {
+			t.Errorf("%s: validateMap() = %v", test.name, err)
+		}
+	}
+}
@@ -80,6 +80,7 @@ type Bullet struct {
 	Position      *Position              `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
 	Rotation      float64                `protobuf:"fixed64,3,opt,name=rotation,proto3" json:"rotation,omitempty"`
 	Expired       bool                   `protobuf:"varint,4,opt,name=expired,proto3" json:"expired,omitempty"`
+	Weapon        string                 `protobuf:"bytes,5,opt,name=weapon,proto3" json:"weapon,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -142,6 +143,13 @@ func (x *Bullet) GetExpired() bool {
 	return false
 }
 
+func (x *Bullet) GetWeapon() string {
+	if x != nil {
+		return x.Weapon
+	}
+	return ""
+}
+
 // Obstacle struct
 type Obstacle struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -272,29 +280,30 @@ func (x *GrassPatch) GetRadius() uint32 {
 	return 0
 }
 
-// GameMap struct
-type GameMap struct {
+// CircleObstacle struct
+type CircleObstacle struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Obstacles     []*Obstacle            `protobuf:"bytes,1,rep,name=obstacles,proto3" json:"obstacles,omitempty"`
-	GrassPatches  []*GrassPatch          `protobuf:"bytes,2,rep,name=grass_patches,json=grassPatches,proto3" json:"grass_patches,omitempty"`
+	X             uint32                 `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             uint32                 `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+	Radius        uint32                 `protobuf:"varint,3,opt,name=radius,proto3" json:"radius,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GameMap) Reset() {
-	*x = GameMap{}
+func (x *CircleObstacle) Reset() {
+	*x = CircleObstacle{}
 	mi := &file_proto_message_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GameMap) String() string {
+func (x *CircleObstacle) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GameMap) ProtoMessage() {}
+func (*CircleObstacle) ProtoMessage() {}
 
-func (x *GameMap) ProtoReflect() protoreflect.Message {
+func (x *CircleObstacle) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_message_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -306,55 +315,65 @@ func (x *GameMap) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GameMap.ProtoReflect.Descriptor instead.
-func (*GameMap) Descriptor() ([]byte, []int) {
+// Deprecated: Use CircleObstacle.ProtoReflect.Descriptor instead.
+func (*CircleObstacle) Descriptor() ([]byte, []int) {
 	return file_proto_message_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *GameMap) GetObstacles() []*Obstacle {
+func (x *CircleObstacle) GetX() uint32 {
 	if x != nil {
-		return x.Obstacles
+		return x.X
 	}
-	return nil
+	return 0
 }
 
-func (x *GameMap) GetGrassPatches() []*GrassPatch {
+func (x *CircleObstacle) GetY() uint32 {
 	if x != nil {
-		return x.GrassPatches
+		return x.Y
 	}
-	return nil
+	return 0
 }
 
-// Player struct
-type Player struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Color         string                 `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
-	Position      *Position              `protobuf:"bytes,4,opt,name=position,proto3" json:"position,omitempty"`
-	IsReady       bool                   `protobuf:"varint,5,opt,name=is_ready,json=isReady,proto3" json:"is_ready,omitempty"`
-	InGrass       bool                   `protobuf:"varint,6,opt,name=in_grass,json=inGrass,proto3" json:"in_grass,omitempty"`
-	Rotation      float64                `protobuf:"fixed64,7,opt,name=rotation,proto3" json:"rotation,omitempty"`
-	Kills         int32                  `protobuf:"varint,8,opt,name=kills,proto3" json:"kills,omitempty"`
-	Health        int32                  `protobuf:"varint,9,opt,name=health,proto3" json:"health,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *CircleObstacle) GetRadius() uint32 {
+	if x != nil {
+		return x.Radius
+	}
+	return 0
 }
 
-func (x *Player) Reset() {
-	*x = Player{}
+// GameMap struct
+type GameMap struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Obstacles       []*Obstacle            `protobuf:"bytes,1,rep,name=obstacles,proto3" json:"obstacles,omitempty"`
+	GrassPatches    []*GrassPatch          `protobuf:"bytes,2,rep,name=grass_patches,json=grassPatches,proto3" json:"grass_patches,omitempty"`
+	Name            string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Width           uint32                 `protobuf:"varint,4,opt,name=width,proto3" json:"width,omitempty"`
+	Height          uint32                 `protobuf:"varint,5,opt,name=height,proto3" json:"height,omitempty"`
+	CircleObstacles []*CircleObstacle      `protobuf:"bytes,6,rep,name=circle_obstacles,json=circleObstacles,proto3" json:"circle_obstacles,omitempty"`
+	SpawnPoints     []*Position            `protobuf:"bytes,7,rep,name=spawn_points,json=spawnPoints,proto3" json:"spawn_points,omitempty"`
+	Seed            int64                  `protobuf:"varint,8,opt,name=seed,proto3" json:"seed,omitempty"`
+	Params          *MapParams             `protobuf:"bytes,9,opt,name=params,proto3,oneof" json:"params,omitempty"`
+	FlagBases       []*FlagBase            `protobuf:"bytes,10,rep,name=flag_bases,json=flagBases,proto3" json:"flag_bases,omitempty"`
+	ControlZones    []*ControlZone         `protobuf:"bytes,11,rep,name=control_zones,json=controlZones,proto3" json:"control_zones,omitempty"`
+	PickupSpawns    []*PickupSpawn         `protobuf:"bytes,12,rep,name=pickup_spawns,json=pickupSpawns,proto3" json:"pickup_spawns,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GameMap) Reset() {
+	*x = GameMap{}
 	mi := &file_proto_message_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Player) String() string {
+func (x *GameMap) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Player) ProtoMessage() {}
+func (*GameMap) ProtoMessage() {}
 
-func (x *Player) ProtoReflect() protoreflect.Message {
+func (x *GameMap) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_message_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -366,104 +385,118 @@ func (x *Player) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Player.ProtoReflect.Descriptor instead.
-func (*Player) Descriptor() ([]byte, []int) {
+// Deprecated: Use GameMap.ProtoReflect.Descriptor instead.
+func (*GameMap) Descriptor() ([]byte, []int) {
 	return file_proto_message_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *Player) GetId() int32 {
+func (x *GameMap) GetObstacles() []*Obstacle {
 	if x != nil {
-		return x.Id
+		return x.Obstacles
 	}
-	return 0
+	return nil
 }
 
-func (x *Player) GetName() string {
+func (x *GameMap) GetGrassPatches() []*GrassPatch {
+	if x != nil {
+		return x.GrassPatches
+	}
+	return nil
+}
+
+func (x *GameMap) GetName() string {
 	if x != nil {
 		return x.Name
 	}
 	return ""
 }
 
-func (x *Player) GetColor() string {
+func (x *GameMap) GetWidth() uint32 {
 	if x != nil {
-		return x.Color
+		return x.Width
 	}
-	return ""
+	return 0
 }
 
-func (x *Player) GetPosition() *Position {
+func (x *GameMap) GetHeight() uint32 {
 	if x != nil {
-		return x.Position
+		return x.Height
 	}
-	return nil
+	return 0
 }
 
-func (x *Player) GetIsReady() bool {
+func (x *GameMap) GetCircleObstacles() []*CircleObstacle {
 	if x != nil {
-		return x.IsReady
+		return x.CircleObstacles
 	}
-	return false
+	return nil
 }
 
-func (x *Player) GetInGrass() bool {
+func (x *GameMap) GetSpawnPoints() []*Position {
 	if x != nil {
-		return x.InGrass
+		return x.SpawnPoints
 	}
-	return false
+	return nil
 }
 
-func (x *Player) GetRotation() float64 {
+func (x *GameMap) GetSeed() int64 {
 	if x != nil {
-		return x.Rotation
+		return x.Seed
 	}
 	return 0
 }
 
-func (x *Player) GetKills() int32 {
+func (x *GameMap) GetParams() *MapParams {
 	if x != nil {
-		return x.Kills
+		return x.Params
 	}
-	return 0
+	return nil
 }
 
-func (x *Player) GetHealth() int32 {
+func (x *GameMap) GetFlagBases() []*FlagBase {
 	if x != nil {
-		return x.Health
+		return x.FlagBases
 	}
-	return 0
+	return nil
 }
 
-// Payload struct
-type Payload struct {
+func (x *GameMap) GetControlZones() []*ControlZone {
+	if x != nil {
+		return x.ControlZones
+	}
+	return nil
+}
+
+func (x *GameMap) GetPickupSpawns() []*PickupSpawn {
+	if x != nil {
+		return x.PickupSpawns
+	}
+	return nil
+}
+
+// PickupSpawn struct
+type PickupSpawn struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Players       []*Player              `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
-	Position      *Position              `protobuf:"bytes,2,opt,name=position,proto3,oneof" json:"position,omitempty"`
-	Bullet        *Bullet                `protobuf:"bytes,3,opt,name=bullet,proto3,oneof" json:"bullet,omitempty"`
-	Map           *GameMap               `protobuf:"bytes,4,opt,name=map,proto3,oneof" json:"map,omitempty"`
-	IsReady       *bool                  `protobuf:"varint,5,opt,name=is_ready,json=isReady,proto3,oneof" json:"is_ready,omitempty"`
-	InGrass       *bool                  `protobuf:"varint,6,opt,name=in_grass,json=inGrass,proto3,oneof" json:"in_grass,omitempty"`
-	Health        *int32                 `protobuf:"varint,7,opt,name=health,proto3,oneof" json:"health,omitempty"`
-	Rotation      *float64               `protobuf:"fixed64,8,opt,name=rotation,proto3,oneof" json:"rotation,omitempty"`
-	Kills         *int32                 `protobuf:"varint,9,opt,name=kills,proto3,oneof" json:"kills,omitempty"`
+	Position      *Position              `protobuf:"bytes,1,opt,name=position,proto3" json:"position,omitempty"`
+	Item          string                 `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Payload) Reset() {
-	*x = Payload{}
+func (x *PickupSpawn) Reset() {
+	*x = PickupSpawn{}
 	mi := &file_proto_message_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Payload) String() string {
+func (x *PickupSpawn) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Payload) ProtoMessage() {}
+func (*PickupSpawn) ProtoMessage() {}
 
-func (x *Payload) ProtoReflect() protoreflect.Message {
+func (x *PickupSpawn) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_message_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -475,100 +508,121 @@ func (x *Payload) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Payload.ProtoReflect.Descriptor instead.
-func (*Payload) Descriptor() ([]byte, []int) {
+// Deprecated: Use PickupSpawn.ProtoReflect.Descriptor instead.
+func (*PickupSpawn) Descriptor() ([]byte, []int) {
 	return file_proto_message_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *Payload) GetPlayers() []*Player {
+func (x *PickupSpawn) GetPosition() *Position {
 	if x != nil {
-		return x.Players
+		return x.Position
 	}
 	return nil
 }
 
-func (x *Payload) GetPosition() *Position {
+func (x *PickupSpawn) GetItem() string {
 	if x != nil {
-		return x.Position
+		return x.Item
 	}
-	return nil
+	return ""
 }
 
-func (x *Payload) GetBullet() *Bullet {
-	if x != nil {
-		return x.Bullet
-	}
-	return nil
+// Pickup struct
+type Pickup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Item          string                 `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
+	Position      *Position              `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
+	Available     bool                   `protobuf:"varint,4,opt,name=available,proto3" json:"available,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Payload) GetMap() *GameMap {
+func (x *Pickup) Reset() {
+	*x = Pickup{}
+	mi := &file_proto_message_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pickup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pickup) ProtoMessage() {}
+
+func (x *Pickup) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_proto_msgTypes[7]
 	if x != nil {
-		return x.Map
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *Payload) GetIsReady() bool {
-	if x != nil && x.IsReady != nil {
-		return *x.IsReady
-	}
-	return false
+// Deprecated: Use Pickup.ProtoReflect.Descriptor instead.
+func (*Pickup) Descriptor() ([]byte, []int) {
+	return file_proto_message_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *Payload) GetInGrass() bool {
-	if x != nil && x.InGrass != nil {
-		return *x.InGrass
+func (x *Pickup) GetId() int32 {
+	if x != nil {
+		return x.Id
 	}
-	return false
+	return 0
 }
 
-func (x *Payload) GetHealth() int32 {
-	if x != nil && x.Health != nil {
-		return *x.Health
+func (x *Pickup) GetItem() string {
+	if x != nil {
+		return x.Item
 	}
-	return 0
+	return ""
 }
 
-func (x *Payload) GetRotation() float64 {
-	if x != nil && x.Rotation != nil {
-		return *x.Rotation
+func (x *Pickup) GetPosition() *Position {
+	if x != nil {
+		return x.Position
 	}
-	return 0
+	return nil
 }
 
-func (x *Payload) GetKills() int32 {
-	if x != nil && x.Kills != nil {
-		return *x.Kills
+func (x *Pickup) GetAvailable() bool {
+	if x != nil {
+		return x.Available
 	}
-	return 0
+	return false
 }
 
-// Message struct
-type Message struct {
+// ControlZone struct
+type ControlZone struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            *int32                 `protobuf:"varint,1,opt,name=id,proto3,oneof" json:"id,omitempty"`
-	Event         string                 `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
-	Time          uint64                 `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
-	Payload       *Payload               `protobuf:"bytes,4,opt,name=payload,proto3,oneof" json:"payload,omitempty"`
+	Position      *Position              `protobuf:"bytes,1,opt,name=position,proto3" json:"position,omitempty"`
+	Radius        uint32                 `protobuf:"varint,2,opt,name=radius,proto3" json:"radius,omitempty"`
+	Owner         *int32                 `protobuf:"varint,3,opt,name=owner,proto3,oneof" json:"owner,omitempty"`
+	Contested     bool                   `protobuf:"varint,4,opt,name=contested,proto3" json:"contested,omitempty"`
+	NextMove      uint32                 `protobuf:"varint,5,opt,name=next_move,json=nextMove,proto3" json:"next_move,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Message) Reset() {
-	*x = Message{}
-	mi := &file_proto_message_proto_msgTypes[7]
+func (x *ControlZone) Reset() {
+	*x = ControlZone{}
+	mi := &file_proto_message_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Message) String() string {
+func (x *ControlZone) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Message) ProtoMessage() {}
+func (*ControlZone) ProtoMessage() {}
 
-func (x *Message) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_message_proto_msgTypes[7]
+func (x *ControlZone) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -579,35 +633,1278 @@ func (x *Message) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Message.ProtoReflect.Descriptor instead.
-func (*Message) Descriptor() ([]byte, []int) {
-	return file_proto_message_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use ControlZone.ProtoReflect.Descriptor instead.
+func (*ControlZone) Descriptor() ([]byte, []int) {
+	return file_proto_message_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Message) GetId() int32 {
-	if x != nil && x.Id != nil {
-		return *x.Id
+func (x *ControlZone) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *ControlZone) GetRadius() uint32 {
+	if x != nil {
+		return x.Radius
 	}
 	return 0
 }
 
-func (x *Message) GetEvent() string {
+func (x *ControlZone) GetOwner() int32 {
+	if x != nil && x.Owner != nil {
+		return *x.Owner
+	}
+	return 0
+}
+
+func (x *ControlZone) GetContested() bool {
 	if x != nil {
-		return x.Event
+		return x.Contested
 	}
-	return ""
+	return false
 }
 
-func (x *Message) GetTime() uint64 {
+func (x *ControlZone) GetNextMove() uint32 {
 	if x != nil {
-		return x.Time
+		return x.NextMove
 	}
 	return 0
 }
 
-func (x *Message) GetPayload() *Payload {
+// SafeZone struct
+type SafeZone struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Center        *Position              `protobuf:"bytes,1,opt,name=center,proto3" json:"center,omitempty"`
+	Radius        float64                `protobuf:"fixed64,2,opt,name=radius,proto3" json:"radius,omitempty"`
+	NextCenter    *Position              `protobuf:"bytes,3,opt,name=next_center,json=nextCenter,proto3" json:"next_center,omitempty"`
+	NextRadius    float64                `protobuf:"fixed64,4,opt,name=next_radius,json=nextRadius,proto3" json:"next_radius,omitempty"`
+	ShrinksIn     uint32                 `protobuf:"varint,5,opt,name=shrinks_in,json=shrinksIn,proto3" json:"shrinks_in,omitempty"`
+	Shrinking     bool                   `protobuf:"varint,6,opt,name=shrinking,proto3" json:"shrinking,omitempty"`
+	Phase         uint32                 `protobuf:"varint,7,opt,name=phase,proto3" json:"phase,omitempty"`
+	Damage        int32                  `protobuf:"varint,8,opt,name=damage,proto3" json:"damage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SafeZone) Reset() {
+	*x = SafeZone{}
+	mi := &file_proto_message_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SafeZone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SafeZone) ProtoMessage() {}
+
+func (x *SafeZone) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_proto_msgTypes[9]
 	if x != nil {
-		return x.Payload
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SafeZone.ProtoReflect.Descriptor instead.
+func (*SafeZone) Descriptor() ([]byte, []int) {
+	return file_proto_message_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SafeZone) GetCenter() *Position {
+	if x != nil {
+		return x.Center
+	}
+	return nil
+}
+
+func (x *SafeZone) GetRadius() float64 {
+	if x != nil {
+		return x.Radius
+	}
+	return 0
+}
+
+func (x *SafeZone) GetNextCenter() *Position {
+	if x != nil {
+		return x.NextCenter
+	}
+	return nil
+}
+
+func (x *SafeZone) GetNextRadius() float64 {
+	if x != nil {
+		return x.NextRadius
+	}
+	return 0
+}
+
+func (x *SafeZone) GetShrinksIn() uint32 {
+	if x != nil {
+		return x.ShrinksIn
+	}
+	return 0
+}
+
+func (x *SafeZone) GetShrinking() bool {
+	if x != nil {
+		return x.Shrinking
+	}
+	return false
+}
+
+func (x *SafeZone) GetPhase() uint32 {
+	if x != nil {
+		return x.Phase
+	}
+	return 0
+}
+
+func (x *SafeZone) GetDamage() int32 {
+	if x != nil {
+		return x.Damage
+	}
+	return 0
+}
+
+// FlagBase struct
+type FlagBase struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Team          int32                  `protobuf:"varint,1,opt,name=team,proto3" json:"team,omitempty"`
+	Position      *Position              `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlagBase) Reset() {
+	*x = FlagBase{}
+	mi := &file_proto_message_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlagBase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlagBase) ProtoMessage() {}
+
+func (x *FlagBase) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlagBase.ProtoReflect.Descriptor instead.
+func (*FlagBase) Descriptor() ([]byte, []int) {
+	return file_proto_message_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *FlagBase) GetTeam() int32 {
+	if x != nil {
+		return x.Team
+	}
+	return 0
+}
+
+func (x *FlagBase) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+// Flag struct
+type Flag struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Team          int32                  `protobuf:"varint,1,opt,name=team,proto3" json:"team,omitempty"`
+	Position      *Position              `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
+	Carrier       *int32                 `protobuf:"varint,3,opt,name=carrier,proto3,oneof" json:"carrier,omitempty"`
+	AtHome        bool                   `protobuf:"varint,4,opt,name=at_home,json=atHome,proto3" json:"at_home,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Flag) Reset() {
+	*x = Flag{}
+	mi := &file_proto_message_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Flag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Flag) ProtoMessage() {}
+
+func (x *Flag) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Flag.ProtoReflect.Descriptor instead.
+func (*Flag) Descriptor() ([]byte, []int) {
+	return file_proto_message_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Flag) GetTeam() int32 {
+	if x != nil {
+		return x.Team
+	}
+	return 0
+}
+
+func (x *Flag) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *Flag) GetCarrier() int32 {
+	if x != nil && x.Carrier != nil {
+		return *x.Carrier
+	}
+	return 0
+}
+
+func (x *Flag) GetAtHome() bool {
+	if x != nil {
+		return x.AtHome
+	}
+	return false
+}
+
+// MapParams struct
+type MapParams struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Width           uint32                 `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
+	Height          uint32                 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	ObstacleDensity float64                `protobuf:"fixed64,3,opt,name=obstacle_density,json=obstacleDensity,proto3" json:"obstacle_density,omitempty"`
+	GrassDensity    float64                `protobuf:"fixed64,4,opt,name=grass_density,json=grassDensity,proto3" json:"grass_density,omitempty"`
+	Symmetry        string                 `protobuf:"bytes,5,opt,name=symmetry,proto3" json:"symmetry,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MapParams) Reset() {
+	*x = MapParams{}
+	mi := &file_proto_message_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MapParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MapParams) ProtoMessage() {}
+
+func (x *MapParams) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MapParams.ProtoReflect.Descriptor instead.
+func (*MapParams) Descriptor() ([]byte, []int) {
+	return file_proto_message_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *MapParams) GetWidth() uint32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *MapParams) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *MapParams) GetObstacleDensity() float64 {
+	if x != nil {
+		return x.ObstacleDensity
+	}
+	return 0
+}
+
+func (x *MapParams) GetGrassDensity() float64 {
+	if x != nil {
+		return x.GrassDensity
+	}
+	return 0
+}
+
+func (x *MapParams) GetSymmetry() string {
+	if x != nil {
+		return x.Symmetry
+	}
+	return ""
+}
+
+// Player struct
+type Player struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Color         string                 `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
+	Position      *Position              `protobuf:"bytes,4,opt,name=position,proto3" json:"position,omitempty"`
+	IsReady       bool                   `protobuf:"varint,5,opt,name=is_ready,json=isReady,proto3" json:"is_ready,omitempty"`
+	InGrass       bool                   `protobuf:"varint,6,opt,name=in_grass,json=inGrass,proto3" json:"in_grass,omitempty"`
+	Rotation      float64                `protobuf:"fixed64,7,opt,name=rotation,proto3" json:"rotation,omitempty"`
+	Kills         int32                  `protobuf:"varint,8,opt,name=kills,proto3" json:"kills,omitempty"`
+	Health        int32                  `protobuf:"varint,9,opt,name=health,proto3" json:"health,omitempty"`
+	Deaths        int32                  `protobuf:"varint,10,opt,name=deaths,proto3" json:"deaths,omitempty"`
+	IsDead        bool                   `protobuf:"varint,11,opt,name=is_dead,json=isDead,proto3" json:"is_dead,omitempty"`
+	Team          int32                  `protobuf:"varint,12,opt,name=team,proto3" json:"team,omitempty"`
+	Score         int32                  `protobuf:"varint,13,opt,name=score,proto3" json:"score,omitempty"`
+	Weapon        string                 `protobuf:"bytes,14,opt,name=weapon,proto3" json:"weapon,omitempty"`
+	Ammo          int32                  `protobuf:"varint,15,opt,name=ammo,proto3" json:"ammo,omitempty"`
+	Reloading     bool                   `protobuf:"varint,16,opt,name=reloading,proto3" json:"reloading,omitempty"`
+	Reserve       int32                  `protobuf:"varint,17,opt,name=reserve,proto3" json:"reserve,omitempty"`
+	Weapons       []string               `protobuf:"bytes,18,rep,name=weapons,proto3" json:"weapons,omitempty"`
+	Effects       []*Effect              `protobuf:"bytes,19,rep,name=effects,proto3" json:"effects,omitempty"`
+	Shield        int32                  `protobuf:"varint,20,opt,name=shield,proto3" json:"shield,omitempty"`
+	IsBot         bool                   `protobuf:"varint,21,opt,name=is_bot,json=isBot,proto3" json:"is_bot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Player) Reset() {
+	*x = Player{}
+	mi := &file_proto_message_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Player) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Player) ProtoMessage() {}
+
+func (x *Player) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Player.ProtoReflect.Descriptor instead.
+func (*Player) Descriptor() ([]byte, []int) {
+	return file_proto_message_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Player) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Player) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Player) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *Player) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *Player) GetIsReady() bool {
+	if x != nil {
+		return x.IsReady
+	}
+	return false
+}
+
+func (x *Player) GetInGrass() bool {
+	if x != nil {
+		return x.InGrass
+	}
+	return false
+}
+
+func (x *Player) GetRotation() float64 {
+	if x != nil {
+		return x.Rotation
+	}
+	return 0
+}
+
+func (x *Player) GetKills() int32 {
+	if x != nil {
+		return x.Kills
+	}
+	return 0
+}
+
+func (x *Player) GetHealth() int32 {
+	if x != nil {
+		return x.Health
+	}
+	return 0
+}
+
+func (x *Player) GetDeaths() int32 {
+	if x != nil {
+		return x.Deaths
+	}
+	return 0
+}
+
+func (x *Player) GetIsDead() bool {
+	if x != nil {
+		return x.IsDead
+	}
+	return false
+}
+
+func (x *Player) GetTeam() int32 {
+	if x != nil {
+		return x.Team
+	}
+	return 0
+}
+
+func (x *Player) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Player) GetWeapon() string {
+	if x != nil {
+		return x.Weapon
+	}
+	return ""
+}
+
+func (x *Player) GetAmmo() int32 {
+	if x != nil {
+		return x.Ammo
+	}
+	return 0
+}
+
+func (x *Player) GetReloading() bool {
+	if x != nil {
+		return x.Reloading
+	}
+	return false
+}
+
+func (x *Player) GetReserve() int32 {
+	if x != nil {
+		return x.Reserve
+	}
+	return 0
+}
+
+func (x *Player) GetWeapons() []string {
+	if x != nil {
+		return x.Weapons
+	}
+	return nil
+}
+
+func (x *Player) GetEffects() []*Effect {
+	if x != nil {
+		return x.Effects
+	}
+	return nil
+}
+
+func (x *Player) GetShield() int32 {
+	if x != nil {
+		return x.Shield
+	}
+	return 0
+}
+
+func (x *Player) GetIsBot() bool {
+	if x != nil {
+		return x.IsBot
+	}
+	return false
+}
+
+// Effect struct
+type Effect struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Effect) Reset() {
+	*x = Effect{}
+	mi := &file_proto_message_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Effect) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Effect) ProtoMessage() {}
+
+func (x *Effect) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Effect.ProtoReflect.Descriptor instead.
+func (*Effect) Descriptor() ([]byte, []int) {
+	return file_proto_message_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Effect) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Effect) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+// GameSettings struct
+type GameSettings struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Mode            string                 `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	KillLimit       int32                  `protobuf:"varint,2,opt,name=kill_limit,json=killLimit,proto3" json:"kill_limit,omitempty"`
+	TimeLimit       uint32                 `protobuf:"varint,3,opt,name=time_limit,json=timeLimit,proto3" json:"time_limit,omitempty"`
+	RespawnDelay    uint32                 `protobuf:"varint,4,opt,name=respawn_delay,json=respawnDelay,proto3" json:"respawn_delay,omitempty"`
+	Teams           bool                   `protobuf:"varint,5,opt,name=teams,proto3" json:"teams,omitempty"`
+	FriendlyFire    bool                   `protobuf:"varint,6,opt,name=friendly_fire,json=friendlyFire,proto3" json:"friendly_fire,omitempty"`
+	CaptureLimit    int32                  `protobuf:"varint,7,opt,name=capture_limit,json=captureLimit,proto3" json:"capture_limit,omitempty"`
+	ScoreLimit      int32                  `protobuf:"varint,8,opt,name=score_limit,json=scoreLimit,proto3" json:"score_limit,omitempty"`
+	SafeZone        bool                   `protobuf:"varint,9,opt,name=safe_zone,json=safeZone,proto3" json:"safe_zone,omitempty"`
+	PlayerCollision bool                   `protobuf:"varint,10,opt,name=player_collision,json=playerCollision,proto3" json:"player_collision,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GameSettings) Reset() {
+	*x = GameSettings{}
+	mi := &file_proto_message_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameSettings) ProtoMessage() {}
+
+func (x *GameSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameSettings.ProtoReflect.Descriptor instead.
+func (*GameSettings) Descriptor() ([]byte, []int) {
+	return file_proto_message_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GameSettings) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *GameSettings) GetKillLimit() int32 {
+	if x != nil {
+		return x.KillLimit
+	}
+	return 0
+}
+
+func (x *GameSettings) GetTimeLimit() uint32 {
+	if x != nil {
+		return x.TimeLimit
+	}
+	return 0
+}
+
+func (x *GameSettings) GetRespawnDelay() uint32 {
+	if x != nil {
+		return x.RespawnDelay
+	}
+	return 0
+}
+
+func (x *GameSettings) GetTeams() bool {
+	if x != nil {
+		return x.Teams
+	}
+	return false
+}
+
+func (x *GameSettings) GetFriendlyFire() bool {
+	if x != nil {
+		return x.FriendlyFire
+	}
+	return false
+}
+
+func (x *GameSettings) GetCaptureLimit() int32 {
+	if x != nil {
+		return x.CaptureLimit
+	}
+	return 0
+}
+
+func (x *GameSettings) GetScoreLimit() int32 {
+	if x != nil {
+		return x.ScoreLimit
+	}
+	return 0
+}
+
+func (x *GameSettings) GetSafeZone() bool {
+	if x != nil {
+		return x.SafeZone
+	}
+	return false
+}
+
+func (x *GameSettings) GetPlayerCollision() bool {
+	if x != nil {
+		return x.PlayerCollision
+	}
+	return false
+}
+
+// TeamScore struct
+type TeamScore struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Team          int32                  `protobuf:"varint,1,opt,name=team,proto3" json:"team,omitempty"`
+	Kills         int32                  `protobuf:"varint,2,opt,name=kills,proto3" json:"kills,omitempty"`
+	Deaths        int32                  `protobuf:"varint,3,opt,name=deaths,proto3" json:"deaths,omitempty"`
+	Players       int32                  `protobuf:"varint,4,opt,name=players,proto3" json:"players,omitempty"`
+	Captures      int32                  `protobuf:"varint,5,opt,name=captures,proto3" json:"captures,omitempty"`
+	Score         int32                  `protobuf:"varint,6,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TeamScore) Reset() {
+	*x = TeamScore{}
+	mi := &file_proto_message_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TeamScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TeamScore) ProtoMessage() {}
+
+func (x *TeamScore) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TeamScore.ProtoReflect.Descriptor instead.
+func (*TeamScore) Descriptor() ([]byte, []int) {
+	return file_proto_message_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *TeamScore) GetTeam() int32 {
+	if x != nil {
+		return x.Team
+	}
+	return 0
+}
+
+func (x *TeamScore) GetKills() int32 {
+	if x != nil {
+		return x.Kills
+	}
+	return 0
+}
+
+func (x *TeamScore) GetDeaths() int32 {
+	if x != nil {
+		return x.Deaths
+	}
+	return 0
+}
+
+func (x *TeamScore) GetPlayers() int32 {
+	if x != nil {
+		return x.Players
+	}
+	return 0
+}
+
+func (x *TeamScore) GetCaptures() int32 {
+	if x != nil {
+		return x.Captures
+	}
+	return 0
+}
+
+func (x *TeamScore) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+// Payload struct
+type Payload struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Players       []*Player              `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	Position      *Position              `protobuf:"bytes,2,opt,name=position,proto3,oneof" json:"position,omitempty"`
+	Bullet        *Bullet                `protobuf:"bytes,3,opt,name=bullet,proto3,oneof" json:"bullet,omitempty"`
+	Map           *GameMap               `protobuf:"bytes,4,opt,name=map,proto3,oneof" json:"map,omitempty"`
+	IsReady       *bool                  `protobuf:"varint,5,opt,name=is_ready,json=isReady,proto3,oneof" json:"is_ready,omitempty"`
+	InGrass       *bool                  `protobuf:"varint,6,opt,name=in_grass,json=inGrass,proto3,oneof" json:"in_grass,omitempty"`
+	Health        *int32                 `protobuf:"varint,7,opt,name=health,proto3,oneof" json:"health,omitempty"`
+	Rotation      *float64               `protobuf:"fixed64,8,opt,name=rotation,proto3,oneof" json:"rotation,omitempty"`
+	Kills         *int32                 `protobuf:"varint,9,opt,name=kills,proto3,oneof" json:"kills,omitempty"`
+	Text          *string                `protobuf:"bytes,10,opt,name=text,proto3,oneof" json:"text,omitempty"`
+	Deaths        *int32                 `protobuf:"varint,11,opt,name=deaths,proto3,oneof" json:"deaths,omitempty"`
+	Settings      *GameSettings          `protobuf:"bytes,12,opt,name=settings,proto3,oneof" json:"settings,omitempty"`
+	Team          *int32                 `protobuf:"varint,13,opt,name=team,proto3,oneof" json:"team,omitempty"`
+	TeamScores    []*TeamScore           `protobuf:"bytes,14,rep,name=team_scores,json=teamScores,proto3" json:"team_scores,omitempty"`
+	Flags         []*Flag                `protobuf:"bytes,15,rep,name=flags,proto3" json:"flags,omitempty"`
+	Zone          *ControlZone           `protobuf:"bytes,16,opt,name=zone,proto3,oneof" json:"zone,omitempty"`
+	SafeZone      *SafeZone              `protobuf:"bytes,17,opt,name=safe_zone,json=safeZone,proto3,oneof" json:"safe_zone,omitempty"`
+	Weapon        *string                `protobuf:"bytes,18,opt,name=weapon,proto3,oneof" json:"weapon,omitempty"`
+	Ammo          *int32                 `protobuf:"varint,19,opt,name=ammo,proto3,oneof" json:"ammo,omitempty"`
+	Reloading     *bool                  `protobuf:"varint,20,opt,name=reloading,proto3,oneof" json:"reloading,omitempty"`
+	Reserve       *int32                 `protobuf:"varint,21,opt,name=reserve,proto3,oneof" json:"reserve,omitempty"`
+	Pickups       []*Pickup              `protobuf:"bytes,22,rep,name=pickups,proto3" json:"pickups,omitempty"`
+	Shield        *int32                 `protobuf:"varint,23,opt,name=shield,proto3,oneof" json:"shield,omitempty"`
+	Difficulty    *string                `protobuf:"bytes,24,opt,name=difficulty,proto3,oneof" json:"difficulty,omitempty"`
+	Target        *int32                 `protobuf:"varint,25,opt,name=target,proto3,oneof" json:"target,omitempty"`
+	Tick          *uint64                `protobuf:"varint,26,opt,name=tick,proto3,oneof" json:"tick,omitempty"`
+	EndTick       *uint64                `protobuf:"varint,27,opt,name=end_tick,json=endTick,proto3,oneof" json:"end_tick,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Payload) Reset() {
+	*x = Payload{}
+	mi := &file_proto_message_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payload) ProtoMessage() {}
+
+func (x *Payload) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payload.ProtoReflect.Descriptor instead.
+func (*Payload) Descriptor() ([]byte, []int) {
+	return file_proto_message_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Payload) GetPlayers() []*Player {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *Payload) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *Payload) GetBullet() *Bullet {
+	if x != nil {
+		return x.Bullet
+	}
+	return nil
+}
+
+func (x *Payload) GetMap() *GameMap {
+	if x != nil {
+		return x.Map
+	}
+	return nil
+}
+
+func (x *Payload) GetIsReady() bool {
+	if x != nil && x.IsReady != nil {
+		return *x.IsReady
+	}
+	return false
+}
+
+func (x *Payload) GetInGrass() bool {
+	if x != nil && x.InGrass != nil {
+		return *x.InGrass
+	}
+	return false
+}
+
+func (x *Payload) GetHealth() int32 {
+	if x != nil && x.Health != nil {
+		return *x.Health
+	}
+	return 0
+}
+
+func (x *Payload) GetRotation() float64 {
+	if x != nil && x.Rotation != nil {
+		return *x.Rotation
+	}
+	return 0
+}
+
+func (x *Payload) GetKills() int32 {
+	if x != nil && x.Kills != nil {
+		return *x.Kills
+	}
+	return 0
+}
+
+func (x *Payload) GetText() string {
+	if x != nil && x.Text != nil {
+		return *x.Text
+	}
+	return ""
+}
+
+func (x *Payload) GetDeaths() int32 {
+	if x != nil && x.Deaths != nil {
+		return *x.Deaths
+	}
+	return 0
+}
+
+func (x *Payload) GetSettings() *GameSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *Payload) GetTeam() int32 {
+	if x != nil && x.Team != nil {
+		return *x.Team
+	}
+	return 0
+}
+
+func (x *Payload) GetTeamScores() []*TeamScore {
+	if x != nil {
+		return x.TeamScores
+	}
+	return nil
+}
+
+func (x *Payload) GetFlags() []*Flag {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+func (x *Payload) GetZone() *ControlZone {
+	if x != nil {
+		return x.Zone
+	}
+	return nil
+}
+
+func (x *Payload) GetSafeZone() *SafeZone {
+	if x != nil {
+		return x.SafeZone
+	}
+	return nil
+}
+
+func (x *Payload) GetWeapon() string {
+	if x != nil && x.Weapon != nil {
+		return *x.Weapon
+	}
+	return ""
+}
+
+func (x *Payload) GetAmmo() int32 {
+	if x != nil && x.Ammo != nil {
+		return *x.Ammo
+	}
+	return 0
+}
+
+func (x *Payload) GetReloading() bool {
+	if x != nil && x.Reloading != nil {
+		return *x.Reloading
+	}
+	return false
+}
+
+func (x *Payload) GetReserve() int32 {
+	if x != nil && x.Reserve != nil {
+		return *x.Reserve
+	}
+	return 0
+}
+
+func (x *Payload) GetPickups() []*Pickup {
+	if x != nil {
+		return x.Pickups
+	}
+	return nil
+}
+
+func (x *Payload) GetShield() int32 {
+	if x != nil && x.Shield != nil {
+		return *x.Shield
+	}
+	return 0
+}
+
+func (x *Payload) GetDifficulty() string {
+	if x != nil && x.Difficulty != nil {
+		return *x.Difficulty
+	}
+	return ""
+}
+
+func (x *Payload) GetTarget() int32 {
+	if x != nil && x.Target != nil {
+		return *x.Target
+	}
+	return 0
+}
+
+func (x *Payload) GetTick() uint64 {
+	if x != nil && x.Tick != nil {
+		return *x.Tick
+	}
+	return 0
+}
+
+func (x *Payload) GetEndTick() uint64 {
+	if x != nil && x.EndTick != nil {
+		return *x.EndTick
+	}
+	return 0
+}
+
+// Message struct
+type Message struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *int32                 `protobuf:"varint,1,opt,name=id,proto3,oneof" json:"id,omitempty"`
+	Event         string                 `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	Time          uint64                 `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
+	Payload       *Payload               `protobuf:"bytes,4,opt,name=payload,proto3,oneof" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_proto_message_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_proto_message_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Message) GetId() int32 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *Message) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *Message) GetTime() uint64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *Message) GetPayload() *Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+// ReplayHeader starts a replay file
+type ReplayHeader struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       uint32                 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	RoomId        uint32                 `protobuf:"varint,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	StartedAt     int64                  `protobuf:"varint,3,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	Seed          int64                  `protobuf:"varint,4,opt,name=seed,proto3" json:"seed,omitempty"`
+	Map           *GameMap               `protobuf:"bytes,5,opt,name=map,proto3" json:"map,omitempty"`
+	Settings      *GameSettings          `protobuf:"bytes,6,opt,name=settings,proto3" json:"settings,omitempty"`
+	Players       []*Player              `protobuf:"bytes,7,rep,name=players,proto3" json:"players,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayHeader) Reset() {
+	*x = ReplayHeader{}
+	mi := &file_proto_message_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayHeader) ProtoMessage() {}
+
+func (x *ReplayHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayHeader.ProtoReflect.Descriptor instead.
+func (*ReplayHeader) Descriptor() ([]byte, []int) {
+	return file_proto_message_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ReplayHeader) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ReplayHeader) GetRoomId() uint32 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *ReplayHeader) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *ReplayHeader) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *ReplayHeader) GetMap() *GameMap {
+	if x != nil {
+		return x.Map
+	}
+	return nil
+}
+
+func (x *ReplayHeader) GetSettings() *GameSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *ReplayHeader) GetPlayers() []*Player {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+// ReplayFrame is a message of a replay and the tick it was sent or received at
+type ReplayFrame struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tick          uint64                 `protobuf:"varint,1,opt,name=tick,proto3" json:"tick,omitempty"`
+	Input         bool                   `protobuf:"varint,2,opt,name=input,proto3" json:"input,omitempty"`
+	Message       *Message               `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayFrame) Reset() {
+	*x = ReplayFrame{}
+	mi := &file_proto_message_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayFrame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayFrame) ProtoMessage() {}
+
+func (x *ReplayFrame) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_message_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayFrame.ProtoReflect.Descriptor instead.
+func (*ReplayFrame) Descriptor() ([]byte, []int) {
+	return file_proto_message_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ReplayFrame) GetTick() uint64 {
+	if x != nil {
+		return x.Tick
+	}
+	return 0
+}
+
+func (x *ReplayFrame) GetInput() bool {
+	if x != nil {
+		return x.Input
+	}
+	return false
+}
+
+func (x *ReplayFrame) GetMessage() *Message {
+	if x != nil {
+		return x.Message
 	}
 	return nil
 }
@@ -618,80 +1915,294 @@ var file_proto_message_proto_rawDesc = []byte{
 	0x0a, 0x13, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x26, 0x0a, 0x08, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
 	0x6e, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x78, 0x12,
-	0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x79, 0x22, 0x75, 0x0a,
-	0x06, 0x42, 0x75, 0x6c, 0x6c, 0x65, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x01, 0x52, 0x02, 0x69, 0x64, 0x12, 0x25, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74,
-	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x50, 0x6f, 0x73, 0x69,
-	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a,
-	0x0a, 0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01,
-	0x52, 0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x78,
-	0x70, 0x69, 0x72, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x78, 0x70,
-	0x69, 0x72, 0x65, 0x64, 0x22, 0x54, 0x0a, 0x08, 0x4f, 0x62, 0x73, 0x74, 0x61, 0x63, 0x6c, 0x65,
-	0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x01, 0x78, 0x12, 0x0c,
-	0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x01, 0x79, 0x12, 0x14, 0x0a, 0x05,
-	0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x77, 0x69, 0x64,
-	0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x40, 0x0a, 0x0a, 0x47, 0x72,
-	0x61, 0x73, 0x73, 0x50, 0x61, 0x74, 0x63, 0x68, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0d, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0d, 0x52, 0x01, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x22, 0x64, 0x0a, 0x07,
+	0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x79, 0x22, 0x8d, 0x01,
+	0x0a, 0x06, 0x42, 0x75, 0x6c, 0x6c, 0x65, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x02, 0x69, 0x64, 0x12, 0x25, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x50, 0x6f, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1a, 0x0a, 0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x65,
+	0x78, 0x70, 0x69, 0x72, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x22, 0x54, 0x0a,
+	0x08, 0x4f, 0x62, 0x73, 0x74, 0x61, 0x63, 0x6c, 0x65, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x01, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x68, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x22, 0x40, 0x0a, 0x0a, 0x47, 0x72, 0x61, 0x73, 0x73, 0x50, 0x61, 0x74, 0x63,
+	0x68, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x01, 0x78, 0x12,
+	0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x01, 0x79, 0x12, 0x16, 0x0a,
+	0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x72,
+	0x61, 0x64, 0x69, 0x75, 0x73, 0x22, 0x44, 0x0a, 0x0e, 0x43, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x4f,
+	0x62, 0x73, 0x74, 0x61, 0x63, 0x6c, 0x65, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x01, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x22, 0xe8, 0x03, 0x0a, 0x07,
 	0x47, 0x61, 0x6d, 0x65, 0x4d, 0x61, 0x70, 0x12, 0x27, 0x0a, 0x09, 0x6f, 0x62, 0x73, 0x74, 0x61,
 	0x63, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x4f, 0x62, 0x73,
 	0x74, 0x61, 0x63, 0x6c, 0x65, 0x52, 0x09, 0x6f, 0x62, 0x73, 0x74, 0x61, 0x63, 0x6c, 0x65, 0x73,
 	0x12, 0x30, 0x0a, 0x0d, 0x67, 0x72, 0x61, 0x73, 0x73, 0x5f, 0x70, 0x61, 0x74, 0x63, 0x68, 0x65,
 	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x47, 0x72, 0x61, 0x73, 0x73, 0x50,
 	0x61, 0x74, 0x63, 0x68, 0x52, 0x0c, 0x67, 0x72, 0x61, 0x73, 0x73, 0x50, 0x61, 0x74, 0x63, 0x68,
-	0x65, 0x73, 0x22, 0xe9, 0x01, 0x0a, 0x06, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x0e, 0x0a,
-	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x12, 0x25, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74,
-	0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x50, 0x6f, 0x73, 0x69,
-	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19,
-	0x0a, 0x08, 0x69, 0x73, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x07, 0x69, 0x73, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x6e, 0x5f,
-	0x67, 0x72, 0x61, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x6e, 0x47,
-	0x72, 0x61, 0x73, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x12, 0x14, 0x0a, 0x05, 0x6b, 0x69, 0x6c, 0x6c, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x05, 0x6b, 0x69, 0x6c, 0x6c, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68,
-	0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x22, 0x94,
-	0x03, 0x0a, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x21, 0x0a, 0x07, 0x70, 0x6c,
-	0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x50, 0x6c,
-	0x61, 0x79, 0x65, 0x72, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x2a, 0x0a,
-	0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x09, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x08, 0x70, 0x6f,
-	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x06, 0x62, 0x75, 0x6c,
-	0x6c, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x42, 0x75, 0x6c, 0x6c,
-	0x65, 0x74, 0x48, 0x01, 0x52, 0x06, 0x62, 0x75, 0x6c, 0x6c, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12,
-	0x1f, 0x0a, 0x03, 0x6d, 0x61, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x47,
-	0x61, 0x6d, 0x65, 0x4d, 0x61, 0x70, 0x48, 0x02, 0x52, 0x03, 0x6d, 0x61, 0x70, 0x88, 0x01, 0x01,
-	0x12, 0x1e, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x08, 0x48, 0x03, 0x52, 0x07, 0x69, 0x73, 0x52, 0x65, 0x61, 0x64, 0x79, 0x88, 0x01, 0x01,
-	0x12, 0x1e, 0x0a, 0x08, 0x69, 0x6e, 0x5f, 0x67, 0x72, 0x61, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01,
-	0x28, 0x08, 0x48, 0x04, 0x52, 0x07, 0x69, 0x6e, 0x47, 0x72, 0x61, 0x73, 0x73, 0x88, 0x01, 0x01,
-	0x12, 0x1b, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05,
-	0x48, 0x05, 0x52, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a,
-	0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x48,
-	0x06, 0x52, 0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x19,
-	0x0a, 0x05, 0x6b, 0x69, 0x6c, 0x6c, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x48, 0x07, 0x52,
-	0x05, 0x6b, 0x69, 0x6c, 0x6c, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x6f,
-	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x62, 0x75, 0x6c, 0x6c, 0x65,
-	0x74, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x6d, 0x61, 0x70, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x69, 0x73,
-	0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x69, 0x6e, 0x5f, 0x67, 0x72,
-	0x61, 0x73, 0x73, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x42, 0x0b,
-	0x0a, 0x09, 0x5f, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x08, 0x0a, 0x06, 0x5f,
-	0x6b, 0x69, 0x6c, 0x6c, 0x73, 0x22, 0x84, 0x01, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x12, 0x13, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52,
-	0x02, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04,
-	0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65,
-	0x12, 0x27, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x08, 0x2e, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x48, 0x01, 0x52, 0x07, 0x70,
-	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x88, 0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69, 0x64,
-	0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x0a, 0x5a, 0x08,
-	0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06,
+	0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x68, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x12, 0x3a, 0x0a, 0x10, 0x63, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x5f, 0x6f,
+	0x62, 0x73, 0x74, 0x61, 0x63, 0x6c, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x43, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x4f, 0x62, 0x73, 0x74, 0x61, 0x63, 0x6c, 0x65, 0x52,
+	0x0f, 0x63, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x4f, 0x62, 0x73, 0x74, 0x61, 0x63, 0x6c, 0x65, 0x73,
+	0x12, 0x2c, 0x0a, 0x0c, 0x73, 0x70, 0x61, 0x77, 0x6e, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73,
+	0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x0b, 0x73, 0x70, 0x61, 0x77, 0x6e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x65,
+	0x65, 0x64, 0x12, 0x27, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x4d, 0x61, 0x70, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x48, 0x00,
+	0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0a, 0x66,
+	0x6c, 0x61, 0x67, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x09, 0x2e, 0x46, 0x6c, 0x61, 0x67, 0x42, 0x61, 0x73, 0x65, 0x52, 0x09, 0x66, 0x6c, 0x61, 0x67,
+	0x42, 0x61, 0x73, 0x65, 0x73, 0x12, 0x31, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x5f, 0x7a, 0x6f, 0x6e, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x43,
+	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5a, 0x6f, 0x6e, 0x65, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x74,
+	0x72, 0x6f, 0x6c, 0x5a, 0x6f, 0x6e, 0x65, 0x73, 0x12, 0x31, 0x0a, 0x0d, 0x70, 0x69, 0x63, 0x6b,
+	0x75, 0x70, 0x5f, 0x73, 0x70, 0x61, 0x77, 0x6e, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0c, 0x2e, 0x50, 0x69, 0x63, 0x6b, 0x75, 0x70, 0x53, 0x70, 0x61, 0x77, 0x6e, 0x52, 0x0c, 0x70,
+	0x69, 0x63, 0x6b, 0x75, 0x70, 0x53, 0x70, 0x61, 0x77, 0x6e, 0x73, 0x42, 0x09, 0x0a, 0x07, 0x5f,
+	0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x22, 0x48, 0x0a, 0x0b, 0x50, 0x69, 0x63, 0x6b, 0x75, 0x70,
+	0x53, 0x70, 0x61, 0x77, 0x6e, 0x12, 0x25, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04,
+	0x69, 0x74, 0x65, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69, 0x74, 0x65, 0x6d,
+	0x22, 0x71, 0x0a, 0x06, 0x50, 0x69, 0x63, 0x6b, 0x75, 0x70, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x74,
+	0x65, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x12, 0x25,
+	0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x09, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62,
+	0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61,
+	0x62, 0x6c, 0x65, 0x22, 0xac, 0x01, 0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5a,
+	0x6f, 0x6e, 0x65, 0x12, 0x25, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61,
+	0x64, 0x69, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x72, 0x61, 0x64, 0x69,
+	0x75, 0x73, 0x12, 0x19, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x48, 0x00, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a,
+	0x09, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x73, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x09, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x73, 0x74, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x6e,
+	0x65, 0x78, 0x74, 0x5f, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08,
+	0x6e, 0x65, 0x78, 0x74, 0x4d, 0x6f, 0x76, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6f, 0x77, 0x6e,
+	0x65, 0x72, 0x22, 0xfd, 0x01, 0x0a, 0x08, 0x53, 0x61, 0x66, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x12,
+	0x21, 0x0a, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x09, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x63, 0x65, 0x6e, 0x74,
+	0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x12, 0x2a, 0x0a, 0x0b, 0x6e, 0x65,
+	0x78, 0x74, 0x5f, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x09, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74,
+	0x43, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x72,
+	0x61, 0x64, 0x69, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x6e, 0x65, 0x78,
+	0x74, 0x52, 0x61, 0x64, 0x69, 0x75, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x68, 0x72, 0x69, 0x6e,
+	0x6b, 0x73, 0x5f, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x73, 0x68, 0x72,
+	0x69, 0x6e, 0x6b, 0x73, 0x49, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x68, 0x72, 0x69, 0x6e, 0x6b,
+	0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73, 0x68, 0x72, 0x69, 0x6e,
+	0x6b, 0x69, 0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x61,
+	0x6d, 0x61, 0x67, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x64, 0x61, 0x6d, 0x61,
+	0x67, 0x65, 0x22, 0x45, 0x0a, 0x08, 0x46, 0x6c, 0x61, 0x67, 0x42, 0x61, 0x73, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x65,
+	0x61, 0x6d, 0x12, 0x25, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x85, 0x01, 0x0a, 0x04, 0x46, 0x6c,
+	0x61, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x12, 0x25, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a,
+	0x07, 0x63, 0x61, 0x72, 0x72, 0x69, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00,
+	0x52, 0x07, 0x63, 0x61, 0x72, 0x72, 0x69, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x07,
+	0x61, 0x74, 0x5f, 0x68, 0x6f, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61,
+	0x74, 0x48, 0x6f, 0x6d, 0x65, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x63, 0x61, 0x72, 0x72, 0x69, 0x65,
+	0x72, 0x22, 0xa5, 0x01, 0x0a, 0x09, 0x4d, 0x61, 0x70, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05,
+	0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x29, 0x0a,
+	0x10, 0x6f, 0x62, 0x73, 0x74, 0x61, 0x63, 0x6c, 0x65, 0x5f, 0x64, 0x65, 0x6e, 0x73, 0x69, 0x74,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x6f, 0x62, 0x73, 0x74, 0x61, 0x63, 0x6c,
+	0x65, 0x44, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x79, 0x12, 0x23, 0x0a, 0x0d, 0x67, 0x72, 0x61, 0x73,
+	0x73, 0x5f, 0x64, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0c, 0x67, 0x72, 0x61, 0x73, 0x73, 0x44, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a,
+	0x08, 0x73, 0x79, 0x6d, 0x6d, 0x65, 0x74, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x73, 0x79, 0x6d, 0x6d, 0x65, 0x74, 0x72, 0x79, 0x22, 0x94, 0x04, 0x0a, 0x06, 0x50, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x6c, 0x6f,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x12, 0x25,
+	0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x09, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x72, 0x65, 0x61, 0x64,
+	0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x73, 0x52, 0x65, 0x61, 0x64, 0x79,
+	0x12, 0x19, 0x0a, 0x08, 0x69, 0x6e, 0x5f, 0x67, 0x72, 0x61, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x69, 0x6e, 0x47, 0x72, 0x61, 0x73, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x72,
+	0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x72,
+	0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6b, 0x69, 0x6c, 0x6c, 0x73,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6b, 0x69, 0x6c, 0x6c, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68,
+	0x65, 0x61, 0x6c, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x61, 0x74, 0x68, 0x73, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x64, 0x65, 0x61, 0x74, 0x68, 0x73, 0x12, 0x17, 0x0a,
+	0x07, 0x69, 0x73, 0x5f, 0x64, 0x65, 0x61, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
+	0x69, 0x73, 0x44, 0x65, 0x61, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63,
+	0x6f, 0x72, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x6d, 0x6d, 0x6f,
+	0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x61, 0x6d, 0x6d, 0x6f, 0x12, 0x1c, 0x0a, 0x09,
+	0x72, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x10, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x09, 0x72, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x72, 0x65, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x73, 0x18,
+	0x12, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x73, 0x12, 0x21,
+	0x0a, 0x07, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x73, 0x18, 0x13, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x07, 0x2e, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x52, 0x07, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74,
+	0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x14, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x06, 0x73, 0x68, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x15, 0x0a, 0x06, 0x69, 0x73, 0x5f,
+	0x62, 0x6f, 0x74, 0x18, 0x15, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x69, 0x73, 0x42, 0x6f, 0x74,
+	0x22, 0x3b, 0x0a, 0x06, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1d,
+	0x0a, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x22, 0xce, 0x02,
+	0x0a, 0x0c, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f,
+	0x64, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6b, 0x69, 0x6c, 0x6c, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6b, 0x69, 0x6c, 0x6c, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x73, 0x70, 0x61, 0x77, 0x6e, 0x5f, 0x64, 0x65, 0x6c, 0x61,
+	0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x72, 0x65, 0x73, 0x70, 0x61, 0x77, 0x6e,
+	0x44, 0x65, 0x6c, 0x61, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x65, 0x61, 0x6d, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x74, 0x65, 0x61, 0x6d, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x66,
+	0x72, 0x69, 0x65, 0x6e, 0x64, 0x6c, 0x79, 0x5f, 0x66, 0x69, 0x72, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0c, 0x66, 0x72, 0x69, 0x65, 0x6e, 0x64, 0x6c, 0x79, 0x46, 0x69, 0x72, 0x65,
+	0x12, 0x23, 0x0a, 0x0d, 0x63, 0x61, 0x70, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x63, 0x61, 0x70, 0x74, 0x75, 0x72, 0x65,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x5f, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x73, 0x63, 0x6f, 0x72,
+	0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x61, 0x66, 0x65, 0x5f, 0x7a,
+	0x6f, 0x6e, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x73, 0x61, 0x66, 0x65, 0x5a,
+	0x6f, 0x6e, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x63, 0x6f,
+	0x6c, 0x6c, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x43, 0x6f, 0x6c, 0x6c, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x99,
+	0x01, 0x0a, 0x09, 0x54, 0x65, 0x61, 0x6d, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x65, 0x61, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x65, 0x61, 0x6d,
+	0x12, 0x14, 0x0a, 0x05, 0x6b, 0x69, 0x6c, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x6b, 0x69, 0x6c, 0x6c, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x61, 0x74, 0x68, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x64, 0x65, 0x61, 0x74, 0x68, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x74,
+	0x75, 0x72, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x63, 0x61, 0x70, 0x74,
+	0x75, 0x72, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x22, 0x8e, 0x09, 0x0a, 0x07, 0x50,
+	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x21, 0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x52, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x2a, 0x0a, 0x08, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x50, 0x6f,
+	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x06, 0x62, 0x75, 0x6c, 0x6c, 0x65, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x42, 0x75, 0x6c, 0x6c, 0x65, 0x74, 0x48, 0x01,
+	0x52, 0x06, 0x62, 0x75, 0x6c, 0x6c, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x03, 0x6d,
+	0x61, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x4d,
+	0x61, 0x70, 0x48, 0x02, 0x52, 0x03, 0x6d, 0x61, 0x70, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a, 0x08,
+	0x69, 0x73, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03,
+	0x52, 0x07, 0x69, 0x73, 0x52, 0x65, 0x61, 0x64, 0x79, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a, 0x08,
+	0x69, 0x6e, 0x5f, 0x67, 0x72, 0x61, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x48, 0x04,
+	0x52, 0x07, 0x69, 0x6e, 0x47, 0x72, 0x61, 0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06,
+	0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x48, 0x05, 0x52, 0x06,
+	0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x72, 0x6f, 0x74,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x48, 0x06, 0x52, 0x08, 0x72,
+	0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x6b, 0x69,
+	0x6c, 0x6c, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x48, 0x07, 0x52, 0x05, 0x6b, 0x69, 0x6c,
+	0x6c, 0x73, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x08, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1b,
+	0x0a, 0x06, 0x64, 0x65, 0x61, 0x74, 0x68, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x48, 0x09,
+	0x52, 0x06, 0x64, 0x65, 0x61, 0x74, 0x68, 0x73, 0x88, 0x01, 0x01, 0x12, 0x2e, 0x0a, 0x08, 0x73,
+	0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e,
+	0x47, 0x61, 0x6d, 0x65, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x48, 0x0a, 0x52, 0x08,
+	0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x74,
+	0x65, 0x61, 0x6d, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x48, 0x0b, 0x52, 0x04, 0x74, 0x65, 0x61,
+	0x6d, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0b, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x73, 0x63, 0x6f,
+	0x72, 0x65, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x54, 0x65, 0x61, 0x6d,
+	0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x0a, 0x74, 0x65, 0x61, 0x6d, 0x53, 0x63, 0x6f, 0x72, 0x65,
+	0x73, 0x12, 0x1b, 0x0a, 0x05, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x05, 0x2e, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x05, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x25,
+	0x0a, 0x04, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x43,
+	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5a, 0x6f, 0x6e, 0x65, 0x48, 0x0c, 0x52, 0x04, 0x7a, 0x6f,
+	0x6e, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x09, 0x73, 0x61, 0x66, 0x65, 0x5f, 0x7a, 0x6f,
+	0x6e, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x53, 0x61, 0x66, 0x65, 0x5a,
+	0x6f, 0x6e, 0x65, 0x48, 0x0d, 0x52, 0x08, 0x73, 0x61, 0x66, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x18, 0x12, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x0e, 0x52, 0x06, 0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12,
+	0x17, 0x0a, 0x04, 0x61, 0x6d, 0x6d, 0x6f, 0x18, 0x13, 0x20, 0x01, 0x28, 0x05, 0x48, 0x0f, 0x52,
+	0x04, 0x61, 0x6d, 0x6d, 0x6f, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09, 0x72, 0x65, 0x6c, 0x6f,
+	0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x14, 0x20, 0x01, 0x28, 0x08, 0x48, 0x10, 0x52, 0x09, 0x72,
+	0x65, 0x6c, 0x6f, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x72,
+	0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x05, 0x48, 0x11, 0x52, 0x07,
+	0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x07, 0x70, 0x69,
+	0x63, 0x6b, 0x75, 0x70, 0x73, 0x18, 0x16, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x50, 0x69,
+	0x63, 0x6b, 0x75, 0x70, 0x52, 0x07, 0x70, 0x69, 0x63, 0x6b, 0x75, 0x70, 0x73, 0x12, 0x1b, 0x0a,
+	0x06, 0x73, 0x68, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x17, 0x20, 0x01, 0x28, 0x05, 0x48, 0x12, 0x52,
+	0x06, 0x73, 0x68, 0x69, 0x65, 0x6c, 0x64, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x64, 0x69,
+	0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x18, 0x18, 0x20, 0x01, 0x28, 0x09, 0x48, 0x13,
+	0x52, 0x0a, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x88, 0x01, 0x01, 0x12,
+	0x1b, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x19, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x14, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04,
+	0x74, 0x69, 0x63, 0x6b, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x04, 0x48, 0x15, 0x52, 0x04, 0x74, 0x69,
+	0x63, 0x6b, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x63,
+	0x6b, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x04, 0x48, 0x16, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69,
+	0x63, 0x6b, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x62, 0x75, 0x6c, 0x6c, 0x65, 0x74, 0x42, 0x06, 0x0a,
+	0x04, 0x5f, 0x6d, 0x61, 0x70, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x69, 0x73, 0x5f, 0x72, 0x65, 0x61,
+	0x64, 0x79, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x69, 0x6e, 0x5f, 0x67, 0x72, 0x61, 0x73, 0x73, 0x42,
+	0x09, 0x0a, 0x07, 0x5f, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x72,
+	0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6b, 0x69, 0x6c, 0x6c,
+	0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x74, 0x65, 0x78, 0x74, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x64,
+	0x65, 0x61, 0x74, 0x68, 0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e,
+	0x67, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x74, 0x65, 0x61, 0x6d, 0x42, 0x07, 0x0a, 0x05, 0x5f,
+	0x7a, 0x6f, 0x6e, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x73, 0x61, 0x66, 0x65, 0x5f, 0x7a, 0x6f,
+	0x6e, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x42, 0x07, 0x0a,
+	0x05, 0x5f, 0x61, 0x6d, 0x6d, 0x6f, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x72, 0x65, 0x6c, 0x6f, 0x61,
+	0x64, 0x69, 0x6e, 0x67, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x68, 0x69, 0x65, 0x6c, 0x64, 0x42, 0x0d, 0x0a, 0x0b, 0x5f,
+	0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x74, 0x69, 0x63, 0x6b, 0x42, 0x0b,
+	0x0a, 0x09, 0x5f, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x63, 0x6b, 0x22, 0x84, 0x01, 0x0a, 0x07,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x13, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x02, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x14, 0x0a, 0x05,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x27, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61,
+	0x64, 0x48, 0x01, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x88, 0x01, 0x01, 0x42,
+	0x05, 0x0a, 0x03, 0x5f, 0x69, 0x64, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x70, 0x61, 0x79, 0x6c, 0x6f,
+	0x61, 0x64, 0x22, 0xde, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x48, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x17, 0x0a,
+	0x07, 0x72, 0x6f, 0x6f, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06,
+	0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x03, 0x6d, 0x61, 0x70,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x4d, 0x61, 0x70,
+	0x52, 0x03, 0x6d, 0x61, 0x70, 0x12, 0x29, 0x0a, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x65,
+	0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73,
+	0x12, 0x21, 0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x07, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x73, 0x22, 0x5b, 0x0a, 0x0b, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x46, 0x72, 0x61,
+	0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x04, 0x74, 0x69, 0x63, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x22, 0x0a, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x42, 0x0a, 0x5a, 0x08, 0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -706,32 +2217,69 @@ func file_proto_message_proto_rawDescGZIP() []byte {
 	return file_proto_message_proto_rawDescData
 }
 
-var file_proto_message_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_message_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_proto_message_proto_goTypes = []any{
-	(*Position)(nil),   // 0: Position
-	(*Bullet)(nil),     // 1: Bullet
-	(*Obstacle)(nil),   // 2: Obstacle
-	(*GrassPatch)(nil), // 3: GrassPatch
-	(*GameMap)(nil),    // 4: GameMap
-	(*Player)(nil),     // 5: Player
-	(*Payload)(nil),    // 6: Payload
-	(*Message)(nil),    // 7: Message
+	(*Position)(nil),       // 0: Position
+	(*Bullet)(nil),         // 1: Bullet
+	(*Obstacle)(nil),       // 2: Obstacle
+	(*GrassPatch)(nil),     // 3: GrassPatch
+	(*CircleObstacle)(nil), // 4: CircleObstacle
+	(*GameMap)(nil),        // 5: GameMap
+	(*PickupSpawn)(nil),    // 6: PickupSpawn
+	(*Pickup)(nil),         // 7: Pickup
+	(*ControlZone)(nil),    // 8: ControlZone
+	(*SafeZone)(nil),       // 9: SafeZone
+	(*FlagBase)(nil),       // 10: FlagBase
+	(*Flag)(nil),           // 11: Flag
+	(*MapParams)(nil),      // 12: MapParams
+	(*Player)(nil),         // 13: Player
+	(*Effect)(nil),         // 14: Effect
+	(*GameSettings)(nil),   // 15: GameSettings
+	(*TeamScore)(nil),      // 16: TeamScore
+	(*Payload)(nil),        // 17: Payload
+	(*Message)(nil),        // 18: Message
+	(*ReplayHeader)(nil),   // 19: ReplayHeader
+	(*ReplayFrame)(nil),    // 20: ReplayFrame
 }
 var file_proto_message_proto_depIdxs = []int32{
-	0, // 0: Bullet.position:type_name -> Position
-	2, // 1: GameMap.obstacles:type_name -> Obstacle
-	3, // 2: GameMap.grass_patches:type_name -> GrassPatch
-	0, // 3: Player.position:type_name -> Position
-	5, // 4: Payload.players:type_name -> Player
-	0, // 5: Payload.position:type_name -> Position
-	1, // 6: Payload.bullet:type_name -> Bullet
-	4, // 7: Payload.map:type_name -> GameMap
-	6, // 8: Message.payload:type_name -> Payload
-	9, // [9:9] is the sub-list for method output_type
-	9, // [9:9] is the sub-list for method input_type
-	9, // [9:9] is the sub-list for extension type_name
-	9, // [9:9] is the sub-list for extension extendee
-	0, // [0:9] is the sub-list for field type_name
+	0,  // 0: Bullet.position:type_name -> Position
+	2,  // 1: GameMap.obstacles:type_name -> Obstacle
+	3,  // 2: GameMap.grass_patches:type_name -> GrassPatch
+	4,  // 3: GameMap.circle_obstacles:type_name -> CircleObstacle
+	0,  // 4: GameMap.spawn_points:type_name -> Position
+	12, // 5: GameMap.params:type_name -> MapParams
+	10, // 6: GameMap.flag_bases:type_name -> FlagBase
+	8,  // 7: GameMap.control_zones:type_name -> ControlZone
+	6,  // 8: GameMap.pickup_spawns:type_name -> PickupSpawn
+	0,  // 9: PickupSpawn.position:type_name -> Position
+	0,  // 10: Pickup.position:type_name -> Position
+	0,  // 11: ControlZone.position:type_name -> Position
+	0,  // 12: SafeZone.center:type_name -> Position
+	0,  // 13: SafeZone.next_center:type_name -> Position
+	0,  // 14: FlagBase.position:type_name -> Position
+	0,  // 15: Flag.position:type_name -> Position
+	0,  // 16: Player.position:type_name -> Position
+	14, // 17: Player.effects:type_name -> Effect
+	13, // 18: Payload.players:type_name -> Player
+	0,  // 19: Payload.position:type_name -> Position
+	1,  // 20: Payload.bullet:type_name -> Bullet
+	5,  // 21: Payload.map:type_name -> GameMap
+	15, // 22: Payload.settings:type_name -> GameSettings
+	16, // 23: Payload.team_scores:type_name -> TeamScore
+	11, // 24: Payload.flags:type_name -> Flag
+	8,  // 25: Payload.zone:type_name -> ControlZone
+	9,  // 26: Payload.safe_zone:type_name -> SafeZone
+	7,  // 27: Payload.pickups:type_name -> Pickup
+	17, // 28: Message.payload:type_name -> Payload
+	5,  // 29: ReplayHeader.map:type_name -> GameMap
+	15, // 30: ReplayHeader.settings:type_name -> GameSettings
+	13, // 31: ReplayHeader.players:type_name -> Player
+	18, // 32: ReplayFrame.message:type_name -> Message
+	33, // [33:33] is the sub-list for method output_type
+	33, // [33:33] is the sub-list for method input_type
+	33, // [33:33] is the sub-list for extension type_name
+	33, // [33:33] is the sub-list for extension extendee
+	0,  // [0:33] is the sub-list for field type_name
 }
 
 func init() { file_proto_message_proto_init() }
@@ -739,15 +2287,18 @@ func file_proto_message_proto_init() {
 	if File_proto_message_proto != nil {
 		return
 	}
-	file_proto_message_proto_msgTypes[6].OneofWrappers = []any{}
-	file_proto_message_proto_msgTypes[7].OneofWrappers = []any{}
+	file_proto_message_proto_msgTypes[5].OneofWrappers = []any{}
+	file_proto_message_proto_msgTypes[8].OneofWrappers = []any{}
+	file_proto_message_proto_msgTypes[11].OneofWrappers = []any{}
+	file_proto_message_proto_msgTypes[17].OneofWrappers = []any{}
+	file_proto_message_proto_msgTypes[18].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_message_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gobwas/ws/wsutil"
+)
+
+// Metrics are kept in-process and exposed on GET /metrics in the Prometheus
+// text exposition format.
+
+type counterVec struct {
+	values map[string]*atomic.Uint64
+	mu     sync.RWMutex
+}
+
+func newCounterVec() *counterVec {
+	return &counterVec{values: map[string]*atomic.Uint64{}}
+}
+
+func (vec *counterVec) inc(label string) {
+	vec.mu.RLock()
+	counter, ok := vec.values[label]
+	vec.mu.RUnlock()
+	if !ok {
+		vec.mu.Lock()
+		if counter, ok = vec.values[label]; !ok {
+			counter = &atomic.Uint64{}
+			vec.values[label] = counter
+		}
+		vec.mu.Unlock()
+	}
+	counter.Add(1)
+}
+
+func (vec *counterVec) snapshot() map[string]uint64 {
+	vec.mu.RLock()
+	defer vec.mu.RUnlock()
+	values := make(map[string]uint64, len(vec.values))
+	for label, counter := range vec.values {
+		values[label] = counter.Load()
+	}
+	return values
+}
+
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+	mu      sync.Mutex
+}
+
+func newHistogram(buckets ...float64) *histogram {
+	return &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, bound := range h.buckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+func (h *histogram) since(start time.Time) {
+	h.observe(time.Since(start).Seconds())
+}
+
+// knownEvents are the events counted under their own label when received.
+// Anything else a client sends is counted as "other", so clients can't grow
+// the label set.
+var knownEvents = map[string]bool{
+	JOIN: true, READY: true, LEAVE: true, SPAWN: true, MOVE: true, SHOOT: true,
+	HIT: true, KICK: true, START: true, DELETE: true, KILLS: true, GAME_OVER: true,
+	DEATH: true, RESPAWN: true, TEAM: true, WEAPON: true, RELOAD: true, HIDDEN: true,
+	REPLAY: true, PAUSE: true, RESUME: true, SEEK: true, SPECTATE: true, FOLLOW: true,
+	ELIMINATED: true, PICKUP: true, PICKUP_SPAWN: true, EFFECT_EXPIRED: true,
+	ADD_BOT: true, REMOVE_BOT: true, FLAG_TAKEN: true, FLAG_DROPPED: true,
+	FLAG_CAPTURED: true, FLAG_RETURNED: true, ZONE: true, SAFE_ZONE: true,
+	SERVER_MESSAGE: true,
+}
+
+// countReceived records a message received from a client.
+func countReceived(event string) {
+	if !knownEvents[event] {
+		event = "other"
+	}
+	metrics.messagesIn.inc(event)
+}
+
+var latencyBuckets = []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1}
+
+var metrics = struct {
+	activeRooms         atomic.Int64
+	connectedPlayers    atomic.Int64
+	connectedSpectators atomic.Int64
+	bulletGoroutines    atomic.Int64
+	bytesSent           atomic.Uint64
+	droppedFrames       atomic.Uint64
+	messagesIn          *counterVec
+	messagesOut         *counterVec
+	broadcastLatency    *histogram
+	bulletStep          *histogram
+	tickDuration        *histogram
+}{
+	messagesIn:       newCounterVec(),
+	messagesOut:      newCounterVec(),
+	broadcastLatency: newHistogram(latencyBuckets...),
+	bulletStep:       newHistogram(latencyBuckets...),
+	tickDuration:     newHistogram(latencyBuckets...),
+}
+
+// writeFrame sends one binary frame and records it against the event type.
+// A failed write counts as a dropped frame.
+func writeFrame(conn net.Conn, event string, data []byte) error {
+	if err := wsutil.WriteServerBinary(conn, data); err != nil {
+		metrics.droppedFrames.Add(1)
+		return err
+	}
+	metrics.messagesOut.inc(event)
+	metrics.bytesSent.Add(uint64(len(data)))
+	return nil
+}
+
+func serveMetrics(w http.ResponseWriter, r *http.Request) {
+	var lobby, inGame int
+	rooms.Range(func(_, value any) bool {
+		room := value.(*Room)
+		room.mu.RLock()
+		if room.IsGameStarted {
+			inGame++
+		} else {
+			lobby++
+		}
+		room.mu.RUnlock()
+		return true
+	})
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	writeGauge(w, "battle_arena_active_rooms", "Rooms with a running event loop.", float64(metrics.activeRooms.Load()))
+	writeGauge(w, "battle_arena_connected_players", "Players with an open WebSocket.", float64(metrics.connectedPlayers.Load()))
+	writeGauge(w, "battle_arena_connected_spectators", "Spectators watching a room.", float64(metrics.connectedSpectators.Load()))
+
+	fmt.Fprintln(w, "# HELP battle_arena_rooms Rooms by state.")
+	fmt.Fprintln(w, "# TYPE battle_arena_rooms gauge")
+	fmt.Fprintf(w, "battle_arena_rooms{state=\"lobby\"} %d\n", lobby)
+	fmt.Fprintf(w, "battle_arena_rooms{state=\"in_game\"} %d\n", inGame)
+
+	writeCounterVec(w, "battle_arena_messages_received_total", "Messages received from clients by event.", metrics.messagesIn)
+	writeCounterVec(w, "battle_arena_messages_sent_total", "Messages written to clients by event.", metrics.messagesOut)
+
+	writeCounter(w, "battle_arena_bytes_sent_total", "Bytes written to WebSocket clients.", metrics.bytesSent.Load())
+	writeCounter(w, "battle_arena_dropped_frames_total", "Frames that failed to be written to a client.", metrics.droppedFrames.Load())
+	writeGauge(w, "battle_arena_bullet_goroutines", "Live bullet movement goroutines.", float64(metrics.bulletGoroutines.Load()))
+
+	writeHistogram(w, "battle_arena_broadcast_duration_seconds", "Time to marshal and fan out one broadcast.", metrics.broadcastLatency)
+	writeHistogram(w, "battle_arena_bullet_step_duration_seconds", "Time spent moving one bullet one step.", metrics.bulletStep)
+	writeHistogram(w, "battle_arena_tick_duration_seconds", "Time the room loop spends handling one message.", metrics.tickDuration)
+}
+
+func writeGauge(w io.Writer, name, help string, value float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", name, help, name, name, formatFloat(value))
+}
+
+func writeCounter(w io.Writer, name, help string, value uint64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, help, name, name, value)
+}
+
+func writeCounterVec(w io.Writer, name, help string, vec *counterVec) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	values := vec.snapshot()
+	labels := make([]string, 0, len(values))
+	for label := range values {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		fmt.Fprintf(w, "%s{event=%s} %d\n", name, strconv.Quote(label), values[label])
+	}
+}
+
+func writeHistogram(w io.Writer, name, help string, h *histogram) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	for i, bound := range h.buckets {
+		fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", name, formatFloat(bound), h.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	fmt.Fprintf(w, "%s_sum %s\n", name, formatFloat(h.sum))
+	fmt.Fprintf(w, "%s_count %d\n", name, h.count)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
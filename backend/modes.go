@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+const (
+	DEFAULT_KILL_LIMIT    = 20
+	DEFAULT_TIME_LIMIT    = 300  // seconds
+	DEFAULT_RESPAWN_DELAY = 3000 // milliseconds
+	MAX_TIME_LIMIT        = 3600
+	MAX_RESPAWN_DELAY     = 30000
+)
+
+// parseGameSettings reads the mode and its limits from the room creation query.
+func parseGameSettings(query url.Values) (*pb.GameSettings, error) {
+	var settings = pb.GameSettings{
+		Mode:         sim.MODE_LAST_MAN_STANDING,
+		KillLimit:    DEFAULT_KILL_LIMIT,
+		TimeLimit:    DEFAULT_TIME_LIMIT,
+		RespawnDelay: DEFAULT_RESPAWN_DELAY,
+		CaptureLimit: DEFAULT_CAPTURE_LIMIT,
+		ScoreLimit:   DEFAULT_SCORE_LIMIT,
+		SafeZone:     true,
+	}
+
+	switch mode := query.Get("mode"); mode {
+	case "", sim.MODE_LAST_MAN_STANDING:
+	case sim.MODE_DEATHMATCH, sim.MODE_CAPTURE_THE_FLAG, sim.MODE_KING_OF_THE_HILL:
+		settings.Mode = mode
+	default:
+		return nil, fmt.Errorf("unknown mode %q", mode)
+	}
+
+	if value := query.Get("killLimit"); value != "" {
+		limit, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("killLimit must be a positive number")
+		}
+		settings.KillLimit = int32(limit)
+	}
+	if value := query.Get("timeLimit"); value != "" {
+		limit, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || limit < 1 || limit > MAX_TIME_LIMIT {
+			return nil, fmt.Errorf("timeLimit must be between 1 and %d seconds", MAX_TIME_LIMIT)
+		}
+		settings.TimeLimit = uint32(limit)
+	}
+	if value := query.Get("respawnDelay"); value != "" {
+		delay, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || delay > MAX_RESPAWN_DELAY {
+			return nil, fmt.Errorf("respawnDelay must be at most %d milliseconds", MAX_RESPAWN_DELAY)
+		}
+		settings.RespawnDelay = uint32(delay)
+	}
+	for key, target := range map[string]*bool{"teams": &settings.Teams, "friendlyFire": &settings.FriendlyFire, "safeZone": &settings.SafeZone, "playerCollision": &settings.PlayerCollision} {
+		if value := query.Get(key); value != "" {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be true or false", key)
+			}
+			*target = enabled
+		}
+	}
+	if value := query.Get("captureLimit"); value != "" {
+		limit, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("captureLimit must be a positive number")
+		}
+		settings.CaptureLimit = int32(limit)
+	}
+	if value := query.Get("scoreLimit"); value != "" {
+		limit, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("scoreLimit must be a positive number")
+		}
+		settings.ScoreLimit = int32(limit)
+	}
+	if settings.Mode == sim.MODE_CAPTURE_THE_FLAG {
+		settings.Teams = true
+	}
+	if settings.Mode != sim.MODE_LAST_MAN_STANDING {
+		settings.SafeZone = false
+	}
+	return &settings, nil
+}
+
+func (room *Room) isDeathmatch() bool {
+	return room.settings.Mode == sim.MODE_DEATHMATCH
+}
+
+// hasRespawns reports whether dead players come back, as in every mode but last man standing.
+func (room *Room) hasRespawns() bool {
+	return sim.HasRespawns(room.settings)
+}
+
+// startMatchTimer ends a respawning match once its time limit runs out.
+func (room *Room) startMatchTimer() {
+	if !room.hasRespawns() {
+		return
+	}
+	time.AfterFunc(sim.TimeLimit(room.settings), func() {
+		room.logger().Info("time limit reached")
+		room.broadcastGameOver()
+	})
+}
+
+// handleDeath eliminates or respawns a player whose health ran out. The caller holds player.mu.
+func (room *Room) handleDeath(player *Player, killerID *int32, killerTeam int32) {
+	// team kills with friendly fire on don't count towards the killer's score
+	var countsAsKill = killerID != nil && sim.CountsAsKill(room.settings, killerTeam, player.Team)
+	var killer any = "none"
+	if killerID != nil {
+		killer = *killerID
+	}
+
+	if room.isOver {
+		return
+	}
+	if !room.hasRespawns() {
+		// stops further hits before the elimination is handled
+		player.IsDead = true
+		room.playerLogger(player.Id).Info("player died", "killer", killer)
+		room.post(&pb.Message{
+			Id:      &player.Id,
+			Event:   ELIMINATED,
+			Payload: &pb.Payload{Target: killerID},
+		})
+		if countsAsKill {
+			room.post(&pb.Message{
+				Id:    killerID,
+				Event: KILLS,
+			})
+		}
+		return
+	}
+
+	player.IsDead = true
+	player.Deaths++
+	var deaths = player.Deaths
+	room.playerLogger(player.Id).Info("player died", "killer", killer, "deaths", deaths)
+	room.dropFlag(player.Id, player.Position)
+
+	go room.broadcastParallel(&pb.Message{
+		Id:      &player.Id,
+		Event:   DEATH,
+		Payload: &pb.Payload{Deaths: &deaths},
+	})
+	if countsAsKill {
+		room.post(&pb.Message{
+			Id:    killerID,
+			Event: KILLS,
+		})
+	}
+	go room.respawnPlayer(player.Id)
+}
+
+// respawnPlayer brings a dead player back after the room's respawn delay.
+func (room *Room) respawnPlayer(ID int32) {
+	time.Sleep(sim.RespawnDelay(room.settings))
+
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+	var player = room.player[ID]
+	if player == nil || room.isOver {
+		return
+	}
+
+	var spawn = room.spawnPosition(ID)
+	player.mu.Lock()
+	player.Health = sim.PLAYER_MAX_HEALTH
+	player.Position = spawn
+	player.InGrass = sim.IsInGrass(room.gameMap, spawn)
+	player.IsDead = false
+	player.Effects = nil
+	player.Shield = 0
+	resetWeapons(player)
+	var data = player.toProto()
+	room.index.update(ID, spawn)
+	player.mu.Unlock()
+
+	room.playerLogger(ID).Info("player respawned")
+	// viewers who can't see the spawn point aren't told where it is
+	var hidden = proto.Clone(data).(*pb.Player)
+	hidden.Position, hidden.InGrass = nil, false
+	room.sight.Lock()
+	defer room.sight.Unlock()
+	var visible = room.sendSighted([]int32{ID}, &pb.Message{
+		Id:      &ID,
+		Event:   RESPAWN,
+		Payload: &pb.Payload{Players: []*pb.Player{data}},
+	}, &pb.Message{
+		Id:      &ID,
+		Event:   RESPAWN,
+		Payload: &pb.Payload{Players: []*pb.Player{hidden}},
+	})
+	for _, viewer := range room.player {
+		if viewer != nil {
+			viewer.mu.Lock()
+			viewer.seen[ID] = visible[viewer.Id]
+			viewer.mu.Unlock()
+		}
+	}
+}
+
+// checkKillLimit ends a deathmatch once a player or their team reaches the kill limit.
+func (room *Room) checkKillLimit(player *Player) {
+	if !room.isDeathmatch() {
+		return
+	}
+	player.mu.RLock()
+	var kills, team = player.Kills, player.Team
+	player.mu.RUnlock()
+	if room.settings.Teams && sim.IsValidTeam(team) {
+		kills = room.teamScores()[team-1].Kills
+	}
+	if sim.ReachedKillLimit(room.settings, kills) {
+		room.logger().Info("kill limit reached")
+		go room.broadcastGameOver()
+	}
+}
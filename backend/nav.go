@@ -0,0 +1,219 @@
+package main
+
+import (
+	"container/heap"
+	"math"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+// how far from a blocked point findPath looks for a walkable cell
+const NAV_SEARCH_RADIUS = 8
+
+// navGrid rasterizes a map into NAV_CELL_SIZE cells. A cell is walkable when
+// a player can stand at its center, i.e. it's clear of every obstacle
+// inflated by sim.PLAYER_SIZE.
+type navGrid struct {
+	obstacles *sim.ObstacleIndex
+	columns   int
+	rows      int
+	walkable  []bool
+}
+
+// newNavGrid builds the grid of a map from its obstacle index. Each room
+// builds one when it's created.
+func newNavGrid(gameMap *pb.GameMap, obstacles *sim.ObstacleIndex) *navGrid {
+	var grid = navGrid{
+		obstacles: obstacles,
+		columns:   int(gameMap.Width / NAV_CELL_SIZE),
+		rows:      int(gameMap.Height / NAV_CELL_SIZE),
+	}
+	grid.walkable = make([]bool, grid.columns*grid.rows)
+	for cell := range grid.walkable {
+		grid.walkable[cell] = !obstacles.IsBlocked(sim.PLAYER_SIZE, grid.center(cell))
+	}
+	return &grid
+}
+
+func (grid *navGrid) center(cell int) *pb.Position {
+	return &pb.Position{
+		X: (float64(cell%grid.columns) + 0.5) * NAV_CELL_SIZE,
+		Y: (float64(cell/grid.columns) + 0.5) * NAV_CELL_SIZE,
+	}
+}
+
+// cellAt returns the cell containing position, or -1 outside the grid.
+func (grid *navGrid) cellAt(position *pb.Position) int {
+	var column, row = int(position.X / NAV_CELL_SIZE), int(position.Y / NAV_CELL_SIZE)
+	if position.X < 0 || position.Y < 0 || column >= grid.columns || row >= grid.rows {
+		return -1
+	}
+	return row*grid.columns + column
+}
+
+func (grid *navGrid) isWalkable(column, row int) bool {
+	return column >= 0 && column < grid.columns && row >= 0 && row < grid.rows &&
+		grid.walkable[row*grid.columns+column]
+}
+
+// nearestWalkable returns the walkable cell closest to position within
+// NAV_SEARCH_RADIUS cells, or -1.
+func (grid *navGrid) nearestWalkable(position *pb.Position) int {
+	var column = int(math.Floor(position.X / NAV_CELL_SIZE))
+	var row = int(math.Floor(position.Y / NAV_CELL_SIZE))
+	var best = -1
+	var bestDistance = math.Inf(1)
+	for dy := -NAV_SEARCH_RADIUS; dy <= NAV_SEARCH_RADIUS; dy++ {
+		for dx := -NAV_SEARCH_RADIUS; dx <= NAV_SEARCH_RADIUS; dx++ {
+			if !grid.isWalkable(column+dx, row+dy) {
+				continue
+			}
+			cell := (row+dy)*grid.columns + column + dx
+			center := grid.center(cell)
+			if distance := math.Hypot(center.X-position.X, center.Y-position.Y); distance < bestDistance {
+				best, bestDistance = cell, distance
+			}
+		}
+	}
+	return best
+}
+
+// isClearPath reports whether a player can walk the straight line between
+// two points without touching an obstacle.
+func (grid *navGrid) isClearPath(from, to *pb.Position) bool {
+	_, blocked := grid.obstacles.Sweep(sim.PLAYER_SIZE, from, to)
+	return !blocked
+}
+
+type navNode struct {
+	cell     int
+	priority float64
+}
+
+type navQueue []navNode
+
+func (queue navQueue) Len() int           { return len(queue) }
+func (queue navQueue) Less(i, j int) bool { return queue[i].priority < queue[j].priority }
+func (queue navQueue) Swap(i, j int)      { queue[i], queue[j] = queue[j], queue[i] }
+func (queue *navQueue) Push(node any)     { *queue = append(*queue, node.(navNode)) }
+func (queue *navQueue) Pop() any {
+	var old = *queue
+	var node = old[len(old)-1]
+	*queue = old[:len(old)-1]
+	return node
+}
+
+// octile is the exact cost between two cells on an open 8-connected grid.
+func (grid *navGrid) octile(from, to int) float64 {
+	var dx = math.Abs(float64(from%grid.columns - to%grid.columns))
+	var dy = math.Abs(float64(from/grid.columns - to/grid.columns))
+	return math.Max(dx, dy) + (math.Sqrt2-1)*math.Min(dx, dy)
+}
+
+// search runs A* over the grid with diagonal moves that never cut a corner
+// and returns the cells from start to goal, or nil when goal is unreachable.
+func (grid *navGrid) search(start, goal int) []int {
+	var cost = map[int]float64{start: 0}
+	var cameFrom = map[int]int{}
+	var queue = navQueue{{cell: start, priority: grid.octile(start, goal)}}
+	var closed = map[int]bool{}
+
+	for queue.Len() > 0 {
+		var current = heap.Pop(&queue).(navNode).cell
+		if current == goal {
+			var cells = []int{goal}
+			for cell := goal; cell != start; {
+				cell = cameFrom[cell]
+				cells = append(cells, cell)
+			}
+			for i, j := 0, len(cells)-1; i < j; i, j = i+1, j-1 {
+				cells[i], cells[j] = cells[j], cells[i]
+			}
+			return cells
+		}
+		if closed[current] {
+			continue
+		}
+		closed[current] = true
+
+		var column, row = current % grid.columns, current / grid.columns
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if (dx == 0 && dy == 0) || !grid.isWalkable(column+dx, row+dy) {
+					continue
+				}
+				if dx != 0 && dy != 0 && (!grid.isWalkable(column+dx, row) || !grid.isWalkable(column, row+dy)) {
+					continue
+				}
+				next := (row+dy)*grid.columns + column + dx
+				step := 1.0
+				if dx != 0 && dy != 0 {
+					step = math.Sqrt2
+				}
+				if known, ok := cost[next]; ok && known <= cost[current]+step {
+					continue
+				}
+				cost[next] = cost[current] + step
+				cameFrom[next] = current
+				heap.Push(&queue, navNode{cell: next, priority: cost[next] + grid.octile(next, goal)})
+			}
+		}
+	}
+	return nil
+}
+
+// smooth drops every waypoint that can be skipped by walking straight to a
+// later one.
+func (grid *navGrid) smooth(from *pb.Position, waypoints []*pb.Position) []*pb.Position {
+	var smoothed []*pb.Position
+	var current = from
+	for i := 0; i < len(waypoints); {
+		var farthest = i
+		for j := len(waypoints) - 1; j > i; j-- {
+			if grid.isClearPath(current, waypoints[j]) {
+				farthest = j
+				break
+			}
+		}
+		smoothed = append(smoothed, waypoints[farthest])
+		current = waypoints[farthest]
+		i = farthest + 1
+	}
+	return smoothed
+}
+
+// findPath returns the waypoints a player at from walks through to reach to,
+// ending at to itself when a player fits there or at the nearest walkable
+// point otherwise. It returns nil when there is no way there.
+func (grid *navGrid) findPath(from, to *pb.Position) []*pb.Position {
+	var start, goal = grid.cellAt(from), grid.cellAt(to)
+	if start < 0 || !grid.walkable[start] {
+		start = grid.nearestWalkable(from)
+	}
+	if goal < 0 || !grid.walkable[goal] {
+		goal = grid.nearestWalkable(to)
+	}
+	if start < 0 || goal < 0 {
+		return nil
+	}
+
+	var cells = grid.search(start, goal)
+	if cells == nil {
+		return nil
+	}
+	var waypoints []*pb.Position
+	for _, cell := range cells[1:] {
+		waypoints = append(waypoints, grid.center(cell))
+	}
+	if !grid.obstacles.IsBlocked(sim.PLAYER_SIZE, to) {
+		if len(waypoints) > 0 {
+			waypoints[len(waypoints)-1] = to
+		} else {
+			waypoints = append(waypoints, to)
+		}
+	} else if len(waypoints) == 0 {
+		waypoints = append(waypoints, grid.center(goal))
+	}
+	return grid.smooth(from, waypoints)
+}
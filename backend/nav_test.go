@@ -0,0 +1,117 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+// checkPath fails the test unless path walks from from to to without a
+// player touching an obstacle.
+func checkPath(t *testing.T, grid *navGrid, from, to *pb.Position, path []*pb.Position) {
+	t.Helper()
+	if len(path) == 0 {
+		t.Fatalf("no path from %v to %v", from, to)
+	}
+	var current = from
+	for _, waypoint := range path {
+		if !grid.isClearPath(current, waypoint) {
+			t.Fatalf("path from %v to %v is blocked between %v and %v", from, to, current, waypoint)
+		}
+		current = waypoint
+	}
+	if current.X != to.X || current.Y != to.Y {
+		t.Fatalf("path from %v to %v ends at %v", from, to, current)
+	}
+}
+
+func TestFindPathCrossroads(t *testing.T) {
+	gameMap, err := loadMapFile("maps/crossroads.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var grid = newNavGrid(gameMap, sim.NewObstacleIndex(gameMap))
+
+	var tests = []struct {
+		name     string
+		from, to *pb.Position
+	}{
+		{"corner to corner", &pb.Position{X: 100, Y: 100}, &pb.Position{X: 1500, Y: 1500}},
+		{"around the pillar", &pb.Position{X: 700, Y: 800}, &pb.Position{X: 900, Y: 800}},
+		{"across a block", &pb.Position{X: 450, Y: 150}, &pb.Position{X: 450, Y: 800}},
+		{"same spot", &pb.Position{X: 800, Y: 200}, &pb.Position{X: 800, Y: 200}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkPath(t, grid, test.from, test.to, grid.findPath(test.from, test.to))
+		})
+	}
+}
+
+func TestFindPathSmoothing(t *testing.T) {
+	var gameMap = &pb.GameMap{Width: 800, Height: 800}
+	var grid = newNavGrid(gameMap, sim.NewObstacleIndex(gameMap))
+	var from, to = &pb.Position{X: 100, Y: 100}, &pb.Position{X: 700, Y: 650}
+	var path = grid.findPath(from, to)
+	if len(path) != 1 {
+		t.Fatalf("open map path has %d waypoints, want 1", len(path))
+	}
+	checkPath(t, grid, from, to, path)
+}
+
+func TestFindPathBlockedGoal(t *testing.T) {
+	gameMap, err := loadMapFile("maps/crossroads.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var grid = newNavGrid(gameMap, sim.NewObstacleIndex(gameMap))
+	var from, inside = &pb.Position{X: 100, Y: 100}, &pb.Position{X: 260, Y: 260}
+	var path = grid.findPath(from, inside)
+	if len(path) == 0 {
+		t.Fatal("no path towards a blocked goal")
+	}
+	var end = path[len(path)-1]
+	if grid.obstacles.IsBlocked(sim.PLAYER_SIZE, end) {
+		t.Fatalf("path ends inside an obstacle at %v", end)
+	}
+	if distance := math.Hypot(end.X-inside.X, end.Y-inside.Y); distance > 2*sim.PLAYER_SIZE {
+		t.Fatalf("path ends %.0f away from the blocked goal", distance)
+	}
+}
+
+func TestDenseGeneratedMapsConnected(t *testing.T) {
+	var params = defaultMapParams()
+	params.ObstacleDensity = MAX_OBSTACLE_DENSITY
+	for seed := int64(1); seed <= 5; seed++ {
+		var gameMap = generateMap(seed, params)
+		if edges := cutOffEdges(newNavGrid(gameMap, sim.NewObstacleIndex(gameMap))); len(edges) != 0 {
+			t.Fatalf("seed %d: %d cells are cut off", seed, len(edges))
+		}
+		if len(gameMap.Obstacles)+len(gameMap.CircleObstacles) == 0 {
+			t.Fatalf("seed %d: no obstacles left", seed)
+		}
+	}
+}
+
+func TestFindPathGeneratedMaps(t *testing.T) {
+	var symmetries = []string{SYMMETRY_NONE, SYMMETRY_HORIZONTAL, SYMMETRY_VERTICAL, SYMMETRY_QUAD, SYMMETRY_ROTATIONAL}
+	for seed := int64(1); seed <= 10; seed++ {
+		var params = defaultMapParams()
+		params.Symmetry = symmetries[seed%int64(len(symmetries))]
+		var gameMap = generateMap(seed, params)
+		var grid = newNavGrid(gameMap, sim.NewObstacleIndex(gameMap))
+		var cells = walkableCells(gameMap)
+		var rng = rand.New(rand.NewSource(seed))
+
+		// generated maps are connected, so every pair of free cells is
+		// reachable
+		for i := 0; i < 10; i++ {
+			from, to := cells[rng.Intn(len(cells))], cells[rng.Intn(len(cells))]
+			start, goal := &pb.Position{X: from[0], Y: from[1]}, &pb.Position{X: to[0], Y: to[1]}
+			checkPath(t, grid, start, goal, grid.findPath(start, goal))
+		}
+	}
+}
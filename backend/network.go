@@ -3,6 +3,7 @@ package main
 import (
 	"net"
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/gobwas/ws/wsutil"
@@ -30,32 +31,59 @@ func parseParams(r *http.Request) (int32, uint16, error) {
 
 func (player *Player) toProto() *pb.Player {
 	return &pb.Player{
-		Id:       player.Id,
-		Health:   player.Health,
-		IsReady:  player.IsReady,
-		Kills:    player.Kills,
-		Rotation: player.Rotation,
-		Position: player.Position,
-		Name:     player.Name,
-		Color:    player.Color,
-		InGrass:  player.InGrass,
+		Id:        player.Id,
+		Health:    player.Health,
+		IsReady:   player.IsReady,
+		Kills:     player.Kills,
+		Rotation:  player.Rotation,
+		Position:  player.Position,
+		Name:      player.Name,
+		Color:     player.Color,
+		InGrass:   player.InGrass,
+		Deaths:    player.Deaths,
+		IsDead:    player.IsDead,
+		Team:      player.Team,
+		Score:     player.Score,
+		Weapon:    player.Weapon,
+		Ammo:      player.Ammo,
+		Reloading: player.Reloading,
+		Reserve:   player.Reserve,
+		Weapons:   slices.Clone(player.Weapons),
+		Effects:   slices.Clone(player.Effects),
+		Shield:    player.Shield,
+		IsBot:     player.IsBot,
 	}
 }
 
+// clientEvents are the events a player's client may send, mapped to whether
+// only the host may send them. Anything else is dropped.
+var clientEvents = map[string]bool{
+	MOVE: false, SHOOT: false, RELOAD: false, WEAPON: false, READY: false, TEAM: false,
+	KICK: true, START: true, ADD_BOT: true, REMOVE_BOT: true,
+}
+
 func handlePlayerConnection(ID int32, Conn *net.Conn, room *Room) {
+	var player = room.player[ID]
 	defer func() {
+		if spectator := player.spectating(); spectator != nil {
+			room.removeSpectator(spectator.ID)
+			return
+		}
+		room.playerLogger(ID).Info("player left")
 		room.mu.RLock()
 		defer room.mu.RUnlock()
 
 		if room.player[ID] != nil {
-			room.broadcast <- &pb.Message{
+			room.post(&pb.Message{
 				Id:    &ID,
 				Event: KICK,
-			}
+			})
 		}
 	}()
 
-	room.player[ID].Conn = Conn
+	player.connect(Conn)
+	metrics.connectedPlayers.Add(1)
+	defer metrics.connectedPlayers.Add(-1)
 
 	var message = pb.Message{
 		Id:    &ID,
@@ -78,17 +106,42 @@ func handlePlayerConnection(ID int32, Conn *net.Conn, room *Room) {
 	for {
 		data, err := wsutil.ReadClientBinary(*Conn)
 		if err != nil {
+			room.playerLogger(ID).Debug("connection closed", "err", err)
 			return
 		}
 
 		var msg pb.Message
 		if err := proto.Unmarshal(data, &msg); err != nil {
+			room.playerLogger(ID).Warn("failed to unmarshal message", "err", err)
 			continue
 		}
 
-		if msg.Id == nil {
+		countReceived(msg.Event)
+
+		if spectator := player.spectating(); spectator != nil {
+			room.handleSpectatorMessage(spectator, &msg)
+			continue
+		}
+		if hostOnly, ok := clientEvents[msg.Event]; !ok || (hostOnly && ID != 0) {
+			continue
+		}
+		// whatever a client claims, its messages are its own, except for
+		// the host's KICK, which names the player to kick
+		var isHostKick = msg.Event == KICK && msg.Id != nil &&
+			*msg.Id >= 0 && int(*msg.Id) < len(room.player)
+		if !isHostKick {
 			msg.Id = &ID
 		}
-		room.broadcast <- &msg
+		if !room.post(&msg) {
+			return
+		}
 	}
 }
+
+// spectating returns the spectator an eliminated player's connection became,
+// or nil while they're playing.
+func (player *Player) spectating() *Spectator {
+	player.mu.RLock()
+	defer player.mu.RUnlock()
+	return player.spectator
+}
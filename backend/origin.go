@@ -0,0 +1,95 @@
+package main
+
+import (
+	"net/url"
+	"os"
+	"strings"
+)
+
+const DEFAULT_ALLOWED_ORIGINS = "https://battle-arena.akashgupta.tech"
+
+// originRule is one entry of ALLOWED_ORIGINS, e.g. "https://example.com",
+// "https://*.example.com", "example.com" (any scheme) or "*". A rule without
+// a port matches any port.
+type originRule struct {
+	scheme   string
+	host     string
+	port     string
+	wildcard bool
+}
+
+type originAllowList struct {
+	any   bool
+	rules []originRule
+}
+
+var allowedOrigins = parseAllowedOrigins(os.Getenv("ALLOWED_ORIGINS"))
+
+func parseAllowedOrigins(value string) *originAllowList {
+	if strings.TrimSpace(value) == "" {
+		value = DEFAULT_ALLOWED_ORIGINS
+	}
+
+	var list originAllowList
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			list.any = true
+			continue
+		}
+
+		var rule originRule
+		if scheme, rest, ok := strings.Cut(entry, "://"); ok {
+			rule.scheme = scheme
+			entry = rest
+		}
+		entry = strings.TrimSuffix(entry, "/")
+		if strings.HasPrefix(entry, "*.") {
+			rule.wildcard = true
+			entry = entry[1:]
+		}
+		if host, port, ok := strings.Cut(entry, ":"); ok {
+			entry, rule.port = host, port
+		}
+		rule.host = entry
+		list.rules = append(list.rules, rule)
+	}
+	return &list
+}
+
+// allows reports whether the browser Origin header value is permitted.
+func (list *originAllowList) allows(origin string) bool {
+	if origin == "" {
+		return false
+	}
+	if list.any {
+		return true
+	}
+
+	parsed, err := url.Parse(strings.ToLower(origin))
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return false
+	}
+
+	var host, port = parsed.Hostname(), parsed.Port()
+	for _, rule := range list.rules {
+		if rule.scheme != "" && rule.scheme != parsed.Scheme {
+			continue
+		}
+		if rule.port != "" && rule.port != port {
+			continue
+		}
+		if rule.wildcard {
+			// "*.example.com" matches "a.example.com" but not "example.com"
+			if strings.HasSuffix(host, rule.host) && len(host) > len(rule.host) {
+				return true
+			}
+		} else if host == rule.host {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestAllowedOrigins(t *testing.T) {
+	var tests = []struct {
+		rules  string
+		origin string
+		want   bool
+	}{
+		{"https://example.com", "https://example.com", true},
+		{"https://example.com", "http://example.com", false},
+		{"https://example.com", "https://EXAMPLE.com", true},
+		{"https://example.com", "https://evil.com", false},
+		{"https://example.com", "https://example.com.evil.com", false},
+		{"example.com", "http://example.com", true},
+		{"example.com", "https://example.com:8443", true},
+		{"https://*.example.com", "https://a.example.com", true},
+		{"https://*.example.com", "https://a.b.example.com", true},
+		{"https://*.example.com", "https://a.example.com:8443", true},
+		{"https://*.example.com", "https://example.com", false},
+		{"https://*.example.com", "https://badexample.com", false},
+		{"http://localhost:3000", "http://localhost:3000", true},
+		{"http://localhost:3000", "http://localhost:4000", false},
+		{"http://localhost:3000", "http://localhost", false},
+		{"https://*.example.com:8443", "https://a.example.com:8443", true},
+		{"https://*.example.com:8443", "https://a.example.com", false},
+		{"https://a.com, https://b.com", "https://b.com", true},
+		{"*", "https://anything.test", true},
+		{"*", "", false},
+		{"", DEFAULT_ALLOWED_ORIGINS, true},
+		{"", "https://example.com", false},
+		{"https://example.com", "null", false},
+	}
+	for _, test := range tests {
+		if got := parseAllowedOrigins(test.rules).allows(test.origin); got != test.want {
+			t.Errorf("%q allows %q = %v, want %v", test.rules, test.origin, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// frames a connection can fall behind by before it's dropped
+	OUTBOX_SIZE = 256
+	// how long writing one frame may take
+	WRITE_TIMEOUT = 5 * time.Second
+)
+
+// outbox writes the frames queued for one connection in order, from a
+// goroutine of its own, so a slow client never holds up the room.
+type outbox struct {
+	conn  net.Conn
+	queue chan outboxFrame
+	// closed once the connection is dropped
+	done   chan struct{}
+	closed sync.Once
+}
+
+// an empty event ends the writer and closes the connection
+type outboxFrame struct {
+	event string
+	data  []byte
+}
+
+func newOutbox(conn net.Conn) *outbox {
+	var out = &outbox{
+		conn:  conn,
+		queue: make(chan outboxFrame, OUTBOX_SIZE),
+		done:  make(chan struct{}),
+	}
+	go out.write()
+	return out
+}
+
+// send queues a frame without blocking. A connection with a full queue has
+// stopped reading and is dropped.
+func (out *outbox) send(event string, data []byte) {
+	select {
+	case <-out.done:
+	case out.queue <- outboxFrame{event, data}:
+	default:
+		metrics.droppedFrames.Add(1)
+		out.close()
+	}
+}
+
+// finish closes the connection once the frames queued before it are written.
+func (out *outbox) finish() {
+	select {
+	case <-out.done:
+	case out.queue <- outboxFrame{}:
+	default:
+		out.close()
+	}
+}
+
+func (out *outbox) write() {
+	for {
+		select {
+		case <-out.done:
+			return
+		case frame := <-out.queue:
+			if frame.event == "" {
+				out.close()
+				return
+			}
+			_ = out.conn.SetWriteDeadline(time.Now().Add(WRITE_TIMEOUT))
+			if err := writeFrame(out.conn, frame.event, frame.data); err != nil {
+				out.close()
+				return
+			}
+		}
+	}
+}
+
+// close drops the connection, which also ends its reader.
+func (out *outbox) close() {
+	out.closed.Do(func() {
+		close(out.done)
+		_ = out.conn.Close()
+	})
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+const (
+	ITEM_AMMO           = "ammo"
+	PICKUP_RADIUS       = 20
+	PICKUP_RESPAWN_TIME = 20 * time.Second
+	PICKUP_SPAWN_COUNT  = 6
+)
+
+// PICKUP_ITEMS are handed out in turn to the pickup spawns of generated maps.
+var PICKUP_ITEMS = []string{sim.WEAPON_SHOTGUN, sim.WEAPON_SNIPER, sim.WEAPON_SMG, ITEM_AMMO, ITEM_AMMO, ITEM_AMMO}
+
+func isValidItem(item string) bool {
+	_, isWeapon := sim.WEAPONS[item]
+	return isWeapon || item == ITEM_AMMO
+}
+
+// pickupState holds the room's pickups, one per pickup spawn of the map, and
+// the power-ups lying around. Its lock is taken after room.mu and player.mu.
+type pickupState struct {
+	pickups  []*pb.Pickup
+	powerUps []*pb.Pickup
+	nextID   int32
+	mu       sync.Mutex
+}
+
+func newPickupState(gameMap *pb.GameMap) *pickupState {
+	var state pickupState
+	for i, spawn := range gameMap.PickupSpawns {
+		state.pickups = append(state.pickups, &pb.Pickup{
+			Id:        int32(i),
+			Item:      spawn.Item,
+			Position:  spawn.Position,
+			Available: true,
+		})
+	}
+	state.nextID = int32(len(state.pickups))
+	return &state
+}
+
+func copyPickup(pickup *pb.Pickup) *pb.Pickup {
+	return &pb.Pickup{
+		Id:        pickup.Id,
+		Item:      pickup.Item,
+		Position:  pickup.Position,
+		Available: pickup.Available,
+	}
+}
+
+func (state *pickupState) toProto() []*pb.Pickup {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	var pickups []*pb.Pickup
+	for _, pickup := range slices.Concat(state.pickups, state.powerUps) {
+		pickups = append(pickups, copyPickup(pickup))
+	}
+	return pickups
+}
+
+// givePickup applies an item to the player and reports whether they took it;
+// a pickup nobody can use stays on the map. The caller holds room.mu and
+// player.mu.
+func (room *Room) givePickup(player *Player, item string) bool {
+	switch {
+	case item == ITEM_AMMO:
+		return giveAmmo(player)
+	case isPowerUp(item):
+		return room.applyPowerUp(player, item)
+	}
+	return giveWeapon(player, item)
+}
+
+func isTouching(player *Player, pickup *pb.Pickup) bool {
+	return sim.Touches(player.Position, pickup.Position, PICKUP_RADIUS)
+}
+
+// collectPickups runs after a player moves and hands them every available
+// pickup they touch. Taken pickups come back after PICKUP_RESPAWN_TIME, taken
+// power-ups are gone for good. The caller holds room.mu and player.mu.
+func (room *Room) collectPickups(player *Player) {
+	var state = room.pickups
+	if player.IsDead {
+		return
+	}
+
+	var taken []*pb.Pickup
+	state.mu.Lock()
+	for _, pickup := range state.pickups {
+		if !pickup.Available || !isTouching(player, pickup) || !room.givePickup(player, pickup.Item) {
+			continue
+		}
+		pickup.Available = false
+		taken = append(taken, copyPickup(pickup))
+
+		var ID = pickup.Id
+		time.AfterFunc(PICKUP_RESPAWN_TIME, func() {
+			room.respawnPickup(ID)
+		})
+	}
+	state.powerUps = slices.DeleteFunc(state.powerUps, func(powerUp *pb.Pickup) bool {
+		if !isTouching(player, powerUp) || !room.givePickup(player, powerUp.Item) {
+			return false
+		}
+		powerUp.Available = false
+		taken = append(taken, copyPickup(powerUp))
+		return true
+	})
+	state.mu.Unlock()
+
+	if len(taken) == 0 {
+		return
+	}
+
+	for _, pickup := range taken {
+		room.playerLogger(player.Id).Debug("pickup taken", "item", pickup.Item, "pickup", pickup.Id)
+	}
+	// players who can't see the taker only see the pickups go
+	var ID = player.Id
+	var msg = &pb.Message{
+		Id:      &ID,
+		Event:   PICKUP,
+		Payload: &pb.Payload{Pickups: taken, Players: []*pb.Player{player.toProto()}},
+	}
+	go func() {
+		room.mu.RLock()
+		defer room.mu.RUnlock()
+		room.broadcastSighted(ID, msg, &pb.Message{Event: PICKUP, Payload: &pb.Payload{Pickups: taken}})
+	}()
+}
+
+func (room *Room) respawnPickup(ID int32) {
+	var state = room.pickups
+	state.mu.Lock()
+	var pickup = state.pickups[ID]
+	pickup.Available = true
+	var data = copyPickup(pickup)
+	state.mu.Unlock()
+
+	go room.broadcastParallel(&pb.Message{
+		Event:   PICKUP_SPAWN,
+		Payload: &pb.Payload{Pickups: []*pb.Pickup{data}},
+	})
+}
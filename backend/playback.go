@@ -0,0 +1,263 @@
+package main
+
+import (
+	"cmp"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"google.golang.org/protobuf/proto"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+// replayCursor walks the messages a replay's clients were sent.
+type replayCursor struct {
+	frames []*pb.ReplayFrame
+	next   int
+	tick   uint64
+}
+
+// newReplayCursor fills in the flight of the bullets, which the replay only
+// has the first and last SHOOT of, across gameMap.
+func newReplayCursor(gameMap *pb.GameMap, frames []*pb.ReplayFrame) *replayCursor {
+	// the tick each bullet's last SHOOT was recorded at
+	var ends = map[float64]uint64{}
+	for _, frame := range frames {
+		if !frame.Input && isBulletStep(frame.Message) {
+			ends[frame.Message.Payload.Bullet.Id] = frame.Tick
+		}
+	}
+
+	var obstacles = sim.NewObstacleIndex(gameMap)
+	var cursor = &replayCursor{}
+	var flights []*pb.ReplayFrame
+	for _, frame := range frames {
+		if frame.Input {
+			continue
+		}
+		cursor.frames = append(cursor.frames, frame)
+		if msg := frame.Message; msg.Event == SHOOT && !isBulletStep(msg) && msg.Payload != nil && msg.Payload.Bullet != nil {
+			end, ended := ends[msg.Payload.Bullet.Id]
+			flights = append(flights, flyBullet(obstacles, msg.Payload.Bullet, frame.Tick, end, ended)...)
+		}
+	}
+	cursor.frames = append(cursor.frames, flights...)
+	slices.SortStableFunc(cursor.frames, func(a, b *pb.ReplayFrame) int { return cmp.Compare(a.Tick, b.Tick) })
+	return cursor
+}
+
+// flyBullet moves a bullet fired at tick once a tick like the room does, up
+// to the tick its last SHOOT was recorded at, or until it's spent when
+// ended is false. Players don't stop it; the recorded SHOOT says where one
+// did.
+func flyBullet(obstacles *sim.ObstacleIndex, fired *pb.Bullet, tick, end uint64, ended bool) []*pb.ReplayFrame {
+	var weapon = sim.WeaponByName(fired.Weapon)
+	var missed = func(from, to *pb.Position, limit float64) (float64, bool) { return 0, false }
+	var bullet = proto.Clone(fired).(*pb.Bullet)
+	var travelled float64
+	var frames []*pb.ReplayFrame
+	for ; !ended || tick < end; tick++ {
+		bullet.Position, travelled, bullet.Expired = sim.MoveBullet(obstacles, weapon, bullet.Position, bullet.Rotation, travelled, missed)
+		if bullet.Expired && ended {
+			break
+		}
+		var ID int32 = BULLET_ID
+		frames = append(frames, &pb.ReplayFrame{
+			Tick:    tick,
+			Message: &pb.Message{Id: &ID, Event: SHOOT, Payload: &pb.Payload{Bullet: proto.Clone(bullet).(*pb.Bullet)}},
+		})
+		if bullet.Expired {
+			break
+		}
+	}
+	return frames
+}
+
+func (cursor *replayCursor) endTick() uint64 {
+	if len(cursor.frames) == 0 {
+		return 0
+	}
+	return cursor.frames[len(cursor.frames)-1].Tick
+}
+
+// nextTick returns the tick of the next message, or false at the end.
+func (cursor *replayCursor) nextTick() (uint64, bool) {
+	if cursor.next >= len(cursor.frames) {
+		return 0, false
+	}
+	return cursor.frames[cursor.next].Tick, true
+}
+
+// advance returns the messages sent up to tick.
+func (cursor *replayCursor) advance(tick uint64) []*pb.Message {
+	var messages []*pb.Message
+	for cursor.next < len(cursor.frames) && cursor.frames[cursor.next].Tick <= tick {
+		messages = append(messages, cursor.frames[cursor.next].Message)
+		cursor.next++
+	}
+	cursor.tick = max(cursor.tick, tick)
+	return messages
+}
+
+// rewind goes back to the start of the replay.
+func (cursor *replayCursor) rewind() {
+	cursor.next, cursor.tick = 0, 0
+}
+
+// seek moves forward to tick and returns what brings a client there. Shots
+// are left out, as their bullets would only flash up before the next
+// update. Going back takes a rewind first.
+func (cursor *replayCursor) seek(tick uint64) []*pb.Message {
+	cursor.tick = max(cursor.tick, tick)
+	var messages []*pb.Message
+	for _, msg := range cursor.advance(tick) {
+		if msg.Event != SHOOT {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+// watchReplay handles /play?replay=NAME, streaming a recorded match to a
+// spectator.
+func watchReplay(w http.ResponseWriter, r *http.Request, name string) {
+	header, frames, err := loadReplay(name)
+	if err != nil {
+		slog.Warn("failed to load replay", "replay", name, "err", err)
+		http.Error(w, "Replay not found", http.StatusNotFound)
+		return
+	}
+
+	conn, _, _, err := ws.UpgradeHTTP(r, w)
+	if err != nil {
+		slog.Error("websocket upgrade failed", "replay", name, "err", err)
+		http.Error(w, "Failed to connect", http.StatusInternalServerError)
+		return
+	}
+	go playReplay(conn, name, header, newReplayCursor(header.Map, frames))
+}
+
+// playReplay sends a replay's messages at the pace they were recorded at,
+// after a REPLAY with the map, settings, starting players and length in
+// ticks. The spectator can send PAUSE, RESUME and SEEK with a tick; each is
+// answered with the same event and the tick playback is at. Anything else
+// is ignored.
+func playReplay(conn net.Conn, name string, header *pb.ReplayHeader, cursor *replayCursor) {
+	defer conn.Close()
+	var logger = slog.With("replay", name)
+	logger.Info("replay playback started")
+	defer logger.Info("replay playback stopped")
+
+	var controls = make(chan *pb.Message)
+	var done = make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(controls)
+		for {
+			data, err := wsutil.ReadClientBinary(conn)
+			if err != nil {
+				return
+			}
+			var msg pb.Message
+			if err := proto.Unmarshal(data, &msg); err != nil {
+				continue
+			}
+			select {
+			case controls <- &msg:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var send = func(messages ...*pb.Message) bool {
+		for _, msg := range messages {
+			data, err := proto.Marshal(msg)
+			if err != nil {
+				logger.Error("failed to marshal message", "event", msg.Event, "err", err)
+				continue
+			}
+			if err := writeFrame(conn, msg.Event, data); err != nil {
+				logger.Debug("connection closed", "err", err)
+				return false
+			}
+		}
+		return true
+	}
+	var status = func(event string) *pb.Message {
+		var tick, endTick = cursor.tick, cursor.endTick()
+		return &pb.Message{Event: event, Payload: &pb.Payload{Tick: &tick, EndTick: &endTick}}
+	}
+
+	// the REPLAY sets the client up from scratch, at the start and before
+	// going back
+	var restart = func() *pb.Message {
+		var info = status(REPLAY)
+		info.Payload.Map = header.Map
+		info.Payload.Settings = header.Settings
+		info.Payload.Players = header.Players
+		return info
+	}
+	if !send(restart()) {
+		return
+	}
+
+	// the wall time playback would have started at, had it never paused
+	var origin = time.Now()
+	var paused bool
+	for {
+		var wait <-chan time.Time
+		if !paused {
+			if !send(cursor.advance(uint64(time.Since(origin) / sim.TICK))...) {
+				return
+			}
+			if tick, ok := cursor.nextTick(); ok {
+				wait = time.After(time.Until(origin.Add(time.Duration(tick) * sim.TICK)))
+			}
+		}
+
+		select {
+		case <-wait:
+		case msg, ok := <-controls:
+			if !ok {
+				return
+			}
+			if !paused && !send(cursor.advance(uint64(time.Since(origin)/sim.TICK))...) {
+				return
+			}
+			switch msg.Event {
+			case PAUSE:
+				paused = true
+			case RESUME:
+				paused = false
+				origin = time.Now().Add(-time.Duration(cursor.tick) * sim.TICK)
+			case SEEK:
+				if msg.Payload == nil || msg.Payload.Tick == nil {
+					continue
+				}
+				var tick = min(*msg.Payload.Tick, cursor.endTick())
+				if tick < cursor.tick {
+					cursor.rewind()
+					if !send(restart()) {
+						return
+					}
+				}
+				if !send(cursor.seek(tick)...) {
+					return
+				}
+				origin = time.Now().Add(-time.Duration(cursor.tick) * sim.TICK)
+			default:
+				continue
+			}
+			if !send(status(msg.Event)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+// Power-ups
+const (
+	POWERUP_HEALTH = "health"
+	POWERUP_SPEED  = "speed"
+	POWERUP_SHIELD = "shield"
+	POWERUP_DAMAGE = "damage"
+)
+
+const (
+	POWERUP_SPAWN_INTERVAL = 15 * time.Second
+	MAX_POWERUPS           = 3
+	HEALTH_PACK_AMOUNT     = 50
+	SPEED_BOOST_FACTOR     = 1.5
+	SPEED_BOOST_DURATION   = 8 * time.Second
+	SHIELD_AMOUNT          = 50
+	SHIELD_DURATION        = 20 * time.Second
+	DAMAGE_MULTIPLIER      = 2
+	DAMAGE_BOOST_DURATION  = 10 * time.Second
+)
+
+var POWERUPS = []string{POWERUP_HEALTH, POWERUP_SPEED, POWERUP_SHIELD, POWERUP_DAMAGE}
+
+func isPowerUp(item string) bool {
+	return slices.Contains(POWERUPS, item)
+}
+
+// hasEffect reports whether a timed effect is active on the player. The
+// caller holds player.mu.
+func (player *Player) hasEffect(effect string, now time.Time) bool {
+	for _, active := range player.Effects {
+		if active.Type == effect && now.UnixMilli() < active.ExpiresAt {
+			return true
+		}
+	}
+	return false
+}
+
+// damageMultiplier is what the player's bullet damage is multiplied by. The
+// caller holds player.mu.
+func (player *Player) damageMultiplier(now time.Time) int32 {
+	if player.hasEffect(POWERUP_DAMAGE, now) {
+		return DAMAGE_MULTIPLIER
+	}
+	return 1
+}
+
+// applyPowerUp gives the player a power-up and reports whether they took it;
+// a health pack is left alone at full health. Taking a timed power-up again
+// restarts its timer. The caller holds room.mu and player.mu.
+func (room *Room) applyPowerUp(player *Player, item string) bool {
+	var duration time.Duration
+	switch item {
+	case POWERUP_HEALTH:
+		if player.Health >= sim.PLAYER_MAX_HEALTH {
+			return false
+		}
+		player.Health = min(player.Health+HEALTH_PACK_AMOUNT, sim.PLAYER_MAX_HEALTH)
+		return true
+	case POWERUP_SPEED:
+		duration = SPEED_BOOST_DURATION
+	case POWERUP_SHIELD:
+		player.Shield = SHIELD_AMOUNT
+		duration = SHIELD_DURATION
+	case POWERUP_DAMAGE:
+		duration = DAMAGE_BOOST_DURATION
+	default:
+		return false
+	}
+
+	var expiresAt = time.Now().Add(duration).UnixMilli()
+	player.Effects = slices.DeleteFunc(player.Effects, func(active *pb.Effect) bool {
+		return active.Type == item
+	})
+	player.Effects = append(player.Effects, &pb.Effect{Type: item, ExpiresAt: expiresAt})
+
+	var ID = player.Id
+	time.AfterFunc(duration, func() {
+		room.expireEffects(ID)
+	})
+	return true
+}
+
+// expireEffects drops the player's effects that have run out and lets
+// everyone know. An effect refreshed in the meantime is still running and
+// stays.
+func (room *Room) expireEffects(ID int32) {
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+	var player = room.player[ID]
+	if player == nil {
+		return
+	}
+
+	var now = time.Now().UnixMilli()
+	player.mu.Lock()
+	var count = len(player.Effects)
+	player.Effects = slices.DeleteFunc(player.Effects, func(active *pb.Effect) bool {
+		return now >= active.ExpiresAt
+	})
+	if len(player.Effects) == count {
+		player.mu.Unlock()
+		return
+	}
+	if !player.hasEffect(POWERUP_SHIELD, time.Now()) {
+		player.Shield = 0
+	}
+	var data = player.toProto()
+	player.mu.Unlock()
+
+	go room.broadcastParallel(&pb.Message{
+		Id:      &ID,
+		Event:   EFFECT_EXPIRED,
+		Payload: &pb.Payload{Players: []*pb.Player{data}},
+	})
+}
+
+// runPowerUps drops a random power-up on a free spot of the map every
+// POWERUP_SPAWN_INTERVAL while fewer than MAX_POWERUPS are lying around,
+// until the match is over.
+func (room *Room) runPowerUps() {
+	ticker := time.NewTicker(POWERUP_SPAWN_INTERVAL)
+	defer ticker.Stop()
+	for range ticker.C {
+		room.mu.RLock()
+		if room.isOver {
+			room.mu.RUnlock()
+			return
+		}
+		room.spawnPowerUp()
+		room.mu.RUnlock()
+	}
+}
+
+// spawnPowerUp places one power-up. The caller holds room.mu.
+func (room *Room) spawnPowerUp() {
+	var state = room.pickups
+	state.mu.Lock()
+	if len(state.powerUps) >= MAX_POWERUPS {
+		state.mu.Unlock()
+		return
+	}
+	var position = state.freePosition(room.gameMap, room.obstacles)
+	if position == nil {
+		state.mu.Unlock()
+		return
+	}
+	var powerUp = &pb.Pickup{
+		Id:        state.nextID,
+		Item:      POWERUPS[rand.Intn(len(POWERUPS))],
+		Position:  position,
+		Available: true,
+	}
+	state.nextID++
+	state.powerUps = append(state.powerUps, powerUp)
+	var data = copyPickup(powerUp)
+	state.mu.Unlock()
+
+	room.logger().Debug("power-up spawned", "item", data.Item, "x", position.X, "y", position.Y)
+	go room.broadcastParallel(&pb.Message{
+		Event:   PICKUP_SPAWN,
+		Payload: &pb.Payload{Pickups: []*pb.Pickup{data}},
+	})
+}
+
+// freePosition samples the map for a spot a player can reach that isn't on
+// top of another pickup. The caller holds state.mu.
+func (state *pickupState) freePosition(gameMap *pb.GameMap, obstacles *sim.ObstacleIndex) *pb.Position {
+	for i := 0; i < sim.SPAWN_SAMPLES; i++ {
+		candidate := &pb.Position{
+			X: sim.PLAYER_SIZE + rand.Float64()*(float64(gameMap.Width)-2*sim.PLAYER_SIZE),
+			Y: sim.PLAYER_SIZE + rand.Float64()*(float64(gameMap.Height)-2*sim.PLAYER_SIZE),
+		}
+		if obstacles.IsBlocked(sim.PLAYER_SIZE, candidate) {
+			continue
+		}
+		var overlaps bool
+		for _, pickup := range slices.Concat(state.pickups, state.powerUps) {
+			if math.Hypot(candidate.X-pickup.Position.X, candidate.Y-pickup.Position.Y) < 2*PICKUP_RADIUS {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			return candidate
+		}
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+// A replay file is a length-delimited ReplayHeader followed by one
+// length-delimited ReplayFrame per message, in the order the room handled
+// them. A bullet's flight is only written where it's fired and where it
+// ends; playback flies it in between. Files are written as NAME.replay.tmp and renamed once the room is
+// deleted, so only finished matches can be listed, downloaded or played.
+const (
+	REPLAY_VERSION   = 2
+	REPLAY_EXTENSION = ".replay"
+	REPLAY_PARTIAL   = ".tmp"
+	// finished replays kept unless MAX_REPLAYS says otherwise; the oldest
+	// go first
+	DEFAULT_MAX_REPLAYS = 100
+)
+
+func replaysDir() string {
+	if dir := os.Getenv("REPLAYS_DIR"); dir != "" {
+		return dir
+	}
+	return "replays"
+}
+
+func maxReplays() int {
+	if limit, err := strconv.Atoi(os.Getenv("MAX_REPLAYS")); err == nil && limit > 0 {
+		return limit
+	}
+	return DEFAULT_MAX_REPLAYS
+}
+
+// recorder writes a room's replay. It does nothing until the game starts,
+// and its lock is taken after every other.
+type recorder struct {
+	dir    string
+	name   string
+	file   *os.File
+	writer *bufio.Writer
+	start  time.Time
+	// the last MOVE each player sent, so repeats aren't written again
+	moves map[int32]*pb.Payload
+	mu    sync.Mutex
+}
+
+func newRecorder(dir string) *recorder {
+	return &recorder{dir: dir}
+}
+
+// begin opens the replay file and writes the header with the players the
+// game starts with. The caller holds room.mu.
+func (rec *recorder) begin(room *Room) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.file != nil {
+		return
+	}
+
+	var now = time.Now()
+	var header = &pb.ReplayHeader{
+		Version:   REPLAY_VERSION,
+		RoomId:    uint32(room.ID),
+		StartedAt: now.UnixMilli(),
+		Seed:      room.gameMap.Seed,
+		Map:       room.gameMap,
+		Settings:  room.settings,
+	}
+	for _, player := range room.player {
+		if player != nil {
+			player.mu.RLock()
+			header.Players = append(header.Players, player.toProto())
+			player.mu.RUnlock()
+		}
+	}
+
+	if err := os.MkdirAll(rec.dir, 0o755); err != nil {
+		room.logger().Warn("replay not recorded", "err", err)
+		return
+	}
+	rec.name = fmt.Sprintf("%d-%d%s", now.UnixMilli(), room.ID, REPLAY_EXTENSION)
+	file, err := os.Create(filepath.Join(rec.dir, rec.name+REPLAY_PARTIAL))
+	if err != nil {
+		room.logger().Warn("replay not recorded", "err", err)
+		return
+	}
+	rec.file, rec.writer, rec.start = file, bufio.NewWriter(file), now
+	if _, err := protodelim.MarshalTo(rec.writer, header); err != nil {
+		room.logger().Warn("failed to write replay header", "err", err)
+	}
+	room.logger().Info("recording replay", "replay", rec.name)
+}
+
+// record appends a message with the tick it happened at. input is set for
+// messages the room handles, from clients, bots and its own timers, and
+// unset for what it sends to clients. A MOVE a player sends again unchanged,
+// as clients do while a key is held, is only written the first time, and
+// of the SHOOTs that move a bullet only the last one is.
+func (rec *recorder) record(msg *pb.Message, input bool) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.writer == nil {
+		return
+	}
+	if isBulletStep(msg) && !msg.Payload.Bullet.Expired {
+		return
+	}
+	if input && msg.Event == MOVE && msg.Id != nil {
+		if last, ok := rec.moves[*msg.Id]; ok && proto.Equal(last, msg.Payload) {
+			return
+		}
+		if rec.moves == nil {
+			rec.moves = map[int32]*pb.Payload{}
+		}
+		rec.moves[*msg.Id] = proto.Clone(msg.Payload).(*pb.Payload)
+	}
+	var frame = &pb.ReplayFrame{
+		Tick:    uint64(time.Since(rec.start) / sim.TICK),
+		Input:   input,
+		Message: msg,
+	}
+	if _, err := protodelim.MarshalTo(rec.writer, frame); err != nil {
+		slog.Warn("failed to write replay frame", "replay", rec.name, "event", msg.Event, "err", err)
+	}
+}
+
+// isBulletStep reports whether msg is a SHOOT that moves a bullet.
+func isBulletStep(msg *pb.Message) bool {
+	return msg.Event == SHOOT && msg.Id != nil && *msg.Id == BULLET_ID &&
+		msg.Payload != nil && msg.Payload.Bullet != nil
+}
+
+// finish flushes the replay and makes it available.
+func (rec *recorder) finish() {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.file == nil {
+		return
+	}
+	var err = errors.Join(rec.writer.Flush(), rec.file.Close())
+	if err == nil {
+		var path = filepath.Join(rec.dir, rec.name)
+		err = os.Rename(path+REPLAY_PARTIAL, path)
+	}
+	if err != nil {
+		slog.Warn("failed to save replay", "replay", rec.name, "err", err)
+	} else {
+		slog.Info("replay saved", "replay", rec.name)
+		pruneReplays(rec.dir, maxReplays())
+	}
+	rec.file, rec.writer = nil, nil
+}
+
+// pruneReplays deletes the oldest finished replays in dir until at most
+// limit are left.
+func pruneReplays(dir string, limit int) {
+	replays, err := findReplays(dir)
+	if err != nil {
+		slog.Warn("failed to list replays to prune", "err", err)
+		return
+	}
+	if len(replays) <= limit {
+		return
+	}
+	for _, replay := range replays[limit:] {
+		if err := os.Remove(filepath.Join(dir, replay.Name)); err != nil {
+			slog.Warn("failed to delete old replay", "replay", replay.Name, "err", err)
+		} else {
+			slog.Info("old replay deleted", "replay", replay.Name)
+		}
+	}
+}
+
+// isReplayName accepts the names of finished replays in the replays
+// directory and nothing that could point elsewhere.
+func isReplayName(name string) bool {
+	return name == filepath.Base(name) && !strings.HasPrefix(name, ".") &&
+		strings.HasSuffix(name, REPLAY_EXTENSION)
+}
+
+// loadReplay reads a whole replay file.
+func loadReplay(name string) (*pb.ReplayHeader, []*pb.ReplayFrame, error) {
+	if !isReplayName(name) {
+		return nil, nil, fmt.Errorf("invalid replay name %q", name)
+	}
+	file, err := os.Open(filepath.Join(replaysDir(), name))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	return readReplay(bufio.NewReader(file))
+}
+
+func readReplay(reader *bufio.Reader) (*pb.ReplayHeader, []*pb.ReplayFrame, error) {
+	var header pb.ReplayHeader
+	if err := protodelim.UnmarshalFrom(reader, &header); err != nil {
+		return nil, nil, fmt.Errorf("reading replay header: %w", err)
+	}
+	if header.Version != REPLAY_VERSION {
+		return nil, nil, fmt.Errorf("unsupported replay version %d", header.Version)
+	}
+	var frames []*pb.ReplayFrame
+	for {
+		var frame pb.ReplayFrame
+		err := protodelim.UnmarshalFrom(reader, &frame)
+		if errors.Is(err, io.EOF) {
+			return &header, frames, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading replay frame %d: %w", len(frames), err)
+		}
+		frames = append(frames, &frame)
+	}
+}
+
+type replayInfo struct {
+	Name       string    `json:"name"`
+	Size       int64     `json:"size"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// findReplays returns the finished replays in dir, newest first.
+func findReplays(dir string) ([]replayInfo, error) {
+	var replays = []replayInfo{}
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !entry.Type().IsRegular() || !isReplayName(entry.Name()) {
+			continue
+		}
+		replays = append(replays, replayInfo{Name: entry.Name(), Size: info.Size(), RecordedAt: info.ModTime()})
+	}
+	sort.Slice(replays, func(i, j int) bool {
+		return replays[i].RecordedAt.After(replays[j].RecordedAt)
+	})
+	return replays, nil
+}
+
+func listReplays(w http.ResponseWriter, r *http.Request) {
+	replays, err := findReplays(replaysDir())
+	if err != nil {
+		slog.Error("failed to list replays", "err", err)
+		http.Error(w, "Failed to list replays", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]replayInfo{"replays": replays})
+}
+
+func downloadReplay(w http.ResponseWriter, r *http.Request) {
+	var name = r.PathValue("name")
+	if !isReplayName(name) {
+		http.Error(w, "Invalid replay", http.StatusBadRequest)
+		return
+	}
+	file, err := os.Open(filepath.Join(replaysDir(), name))
+	if err != nil {
+		http.Error(w, "Replay not found", http.StatusNotFound)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(w, "Replay not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	http.ServeContent(w, r, name, info.ModTime(), file)
+}
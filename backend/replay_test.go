@@ -0,0 +1,158 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+func TestRecorderRoundTrip(t *testing.T) {
+	var dir = t.TempDir()
+	t.Setenv("REPLAYS_DIR", dir)
+	var room = &Room{
+		ID:       7,
+		gameMap:  generateMap(3, defaultMapParams()),
+		settings: &pb.GameSettings{Mode: "deathmatch", KillLimit: 5},
+	}
+	var player = &Player{}
+	initializePlayer(player, 0, &pb.Position{X: 100, Y: 100})
+	player.Name = "a"
+	room.player[0] = player
+
+	var rec = newRecorder(dir)
+	rec.record(&pb.Message{Event: READY}, true)
+	rec.begin(room)
+	var ID int32 = 0
+	rec.record(&pb.Message{Id: &ID, Event: MOVE, Payload: &pb.Payload{Position: &pb.Position{X: 1}}}, true)
+	rec.record(&pb.Message{Id: &ID, Event: MOVE, Payload: &pb.Payload{Position: &pb.Position{X: 1}}}, true)
+	rec.record(&pb.Message{Id: &ID, Event: MOVE, Payload: &pb.Payload{Position: &pb.Position{X: 104, Y: 100}}}, false)
+	var bulletID int32 = BULLET_ID
+	rec.record(&pb.Message{Id: &bulletID, Event: SHOOT, Payload: &pb.Payload{Bullet: &pb.Bullet{Id: 1}}}, false)
+	rec.record(&pb.Message{Id: &bulletID, Event: SHOOT, Payload: &pb.Payload{Bullet: &pb.Bullet{Id: 1, Expired: true}}}, false)
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 || isReplayName(entries[0].Name()) {
+		t.Fatalf("a replay being recorded must not look finished: %v", entries)
+	}
+	rec.finish()
+
+	header, frames, err := loadReplay(rec.name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.RoomId != 7 || header.Seed != 3 || header.Settings.KillLimit != 5 ||
+		len(header.Players) != 1 || header.Players[0].Name != "a" || len(header.Map.Obstacles) != len(room.gameMap.Obstacles) {
+		t.Fatalf("header = %v", header)
+	}
+	// the READY came before the game started, the repeated MOVE is written
+	// once and of the bullet's flight only where it ended
+	if len(frames) != 3 || !frames[0].Input || frames[1].Input || frames[1].Message.Payload.Position.X != 104 ||
+		!frames[2].Message.Payload.Bullet.Expired {
+		t.Fatalf("frames = %v", frames)
+	}
+	if _, _, err := loadReplay(filepath.Join("..", rec.name)); err == nil {
+		t.Fatal("loaded a replay outside the replays directory")
+	}
+}
+
+func TestReplayCursor(t *testing.T) {
+	var frame = func(tick uint64, event string, input bool) *pb.ReplayFrame {
+		return &pb.ReplayFrame{Tick: tick, Input: input, Message: &pb.Message{Event: event}}
+	}
+	var events = func(messages []*pb.Message) []string {
+		var names []string
+		for _, msg := range messages {
+			names = append(names, msg.Event)
+		}
+		return names
+	}
+	var cursor = newReplayCursor(&pb.GameMap{Width: 800, Height: 800}, []*pb.ReplayFrame{
+		frame(0, START, false),
+		frame(0, SPAWN, false),
+		frame(3, MOVE, true),
+		frame(3, MOVE, false),
+		frame(5, SHOOT, false),
+		frame(9, HIT, false),
+		frame(20, GAME_OVER, false),
+	})
+
+	if got := events(cursor.advance(4)); len(got) != 3 || got[2] != MOVE {
+		t.Fatalf("advance(4) = %v, want START, SPAWN and the sent MOVE", got)
+	}
+	if tick, ok := cursor.nextTick(); !ok || tick != 5 {
+		t.Fatalf("nextTick() = %d, %v", tick, ok)
+	}
+	if got := events(cursor.seek(10)); len(got) != 1 || got[0] != HIT {
+		t.Fatalf("seek(10) = %v, want the HIT without the shot", got)
+	}
+	if got := events(cursor.seek(2)); len(got) != 0 || cursor.tick != 10 {
+		t.Fatalf("seek(2) = %v at tick %d, want to stay at tick 10", got, cursor.tick)
+	}
+	cursor.rewind()
+	if got := events(cursor.seek(2)); len(got) != 2 || got[0] != START || got[1] != SPAWN {
+		t.Fatalf("seek(2) after rewind() = %v, want to start over", got)
+	}
+	if cursor.endTick() != 20 {
+		t.Fatalf("endTick() = %d", cursor.endTick())
+	}
+}
+
+func TestReplayFliesBullets(t *testing.T) {
+	var shooter, bulletID int32 = 0, BULLET_ID
+	var fired = func(ID float64) *pb.Message {
+		return &pb.Message{Id: &shooter, Event: SHOOT, Payload: &pb.Payload{Bullet: &pb.Bullet{
+			Id: ID, Position: &pb.Position{X: 100, Y: 100}, Weapon: sim.WEAPON_PISTOL,
+		}}}
+	}
+	var cursor = newReplayCursor(&pb.GameMap{Width: 2000, Height: 400}, []*pb.ReplayFrame{
+		{Tick: 2, Message: fired(1)},
+		{Tick: 2, Message: fired(2)},
+		{Tick: 6, Message: &pb.Message{Id: &bulletID, Event: SHOOT, Payload: &pb.Payload{Bullet: &pb.Bullet{
+			Id: 1, Position: &pb.Position{X: 150, Y: 100}, Expired: true,
+		}}}},
+	})
+
+	var steps = map[float64][]*pb.Bullet{}
+	for _, msg := range cursor.advance(1000) {
+		if isBulletStep(msg) {
+			steps[msg.Payload.Bullet.Id] = append(steps[msg.Payload.Bullet.Id], msg.Payload.Bullet)
+		}
+	}
+	// the first bullet flies until its recorded hit, the second to the end
+	// of its range
+	if len(steps[1]) != 5 || steps[1][0].Position.X != 100+sim.BULLET_SPEED || steps[1][3].Expired || steps[1][4].Position.X != 150 {
+		t.Fatalf("steps of the bullet that hit = %v", steps[1])
+	}
+	var weapon = sim.WeaponByName(sim.WEAPON_PISTOL)
+	var last = steps[2][len(steps[2])-1]
+	if !last.Expired || last.Position.X != 100+weapon.Range {
+		t.Fatalf("last step of the bullet that missed = %v", last)
+	}
+}
+
+func TestPruneReplays(t *testing.T) {
+	var dir = t.TempDir()
+	var names = []string{"1-1.replay", "2-1.replay", "3-1.replay"}
+	for i, name := range names {
+		var path = filepath.Join(dir, name)
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		var at = time.Unix(int64(1000+i), 0)
+		os.Chtimes(path, at, at)
+	}
+	os.WriteFile(filepath.Join(dir, "4-1.replay.tmp"), nil, 0o644)
+
+	pruneReplays(dir, 2)
+	var left []string
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		left = append(left, entry.Name())
+	}
+	if len(left) != 3 || left[0] != "2-1.replay" || left[1] != "3-1.replay" || left[2] != "4-1.replay.tmp" {
+		t.Fatalf("left %v, want the two newest and the one being recorded", left)
+	}
+}
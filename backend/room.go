@@ -6,8 +6,8 @@ import (
 	"time"
 
 	pb "battle-arena/message"
+	"battle-arena/sim"
 
-	"github.com/gobwas/ws/wsutil"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -25,13 +25,64 @@ const (
 	DELETE    = "Delete"
 	KILLS     = "Kills"
 	GAME_OVER = "Game Over"
+	DEATH     = "Death"
+	RESPAWN   = "Respawn"
+	TEAM      = "Team"
+	WEAPON    = "Weapon"
+	RELOAD    = "Reload"
+	HIDDEN    = "Hidden"
+	REPLAY    = "Replay"
+	PAUSE     = "Pause"
+	RESUME    = "Resume"
+	SEEK      = "Seek"
+	SPECTATE  = "Spectate"
+	FOLLOW    = "Follow"
+	// a last man standing death, handled by the room like a KICK
+	ELIMINATED = "Eliminated"
+
+	PICKUP       = "Pickup"
+	PICKUP_SPAWN = "Pickup Spawn"
+
+	EFFECT_EXPIRED = "Effect Expired"
+
+	ADD_BOT    = "Add Bot"
+	REMOVE_BOT = "Remove Bot"
+
+	FLAG_TAKEN    = "Flag Taken"
+	FLAG_DROPPED  = "Flag Dropped"
+	FLAG_CAPTURED = "Flag Captured"
+	FLAG_RETURNED = "Flag Returned"
+	ZONE          = "Zone"
+	SAFE_ZONE     = "Safe Zone"
+
+	SERVER_MESSAGE = "Server Message"
 )
 
+// BULLET_ID stands in for the player of the SHOOTs that move a bullet, as
+// opposed to the one that fires it.
+const BULLET_ID = 255
+
 type Room struct {
 	ID            uint16
 	IsGameStarted bool
+	CreatedAt     time.Time
 	player        [6]*Player
+	gameMap       *pb.GameMap
+	settings      *pb.GameSettings
+	ctf           *ctfState
+	koth          *kothState
+	safeZone      *safeZoneState
+	pickups       *pickupState
+	index         *playerIndex
+	obstacles     *sim.ObstacleIndex
+	nav           *navGrid
+	recorder      *recorder
+	spectators    map[int32]*Spectator
+	nextSpectator int32
+	isOver        bool
+	sight         sync.Mutex
 	broadcast     chan *pb.Message
+	done          chan struct{}
 	Time          uint8
 	mu            sync.RWMutex
 }
@@ -41,110 +92,170 @@ var rooms sync.Map
 var ROOM_ID uint16 = 1
 
 func (room *Room) run() {
+	metrics.activeRooms.Add(1)
 	defer func() {
+		// first, as senders blocked in post may hold room.mu
+		close(room.done)
 		rooms.Delete(room.ID)
+		metrics.activeRooms.Add(-1)
+		room.recorder.finish()
+		room.removeSpectators()
+		room.logger().Info("room deleted")
 	}()
 	for msg := range room.broadcast {
+		if msg.Event != DELETE {
+			room.recorder.record(msg, true)
+		}
 		switch msg.Event {
 		case DELETE:
 			return
 		case START:
-			go room.startGame(msg)
+			go room.timed(room.startGame, msg)
 		case MOVE:
-			go room.broadcastMove(msg)
+			go room.timed(room.broadcastMove, msg)
 		case KICK:
-			go room.kickPlayer(msg)
+			go room.timed(room.kickPlayer, msg)
+		case ELIMINATED:
+			go room.timed(room.eliminatePlayer, msg)
+		case TEAM:
+			go room.timed(room.changeTeam, msg)
 		case READY:
-			go func(msg *pb.Message, room *Room) {
-				room.broadcastParallel(msg)
-				room.mu.RLock()
-				defer room.mu.RUnlock()
-				room.player[*msg.Id].mu.Lock()
-				room.player[*msg.Id].IsReady = *msg.Payload.IsReady
-				room.player[*msg.Id].mu.Unlock()
-			}(msg, room)
+			go room.timed(room.setReady, msg)
 		case SHOOT:
-			if *msg.Id == 255 {
-				go room.broadcastParallel(msg)
+			if *msg.Id == BULLET_ID {
+				go room.timed(room.broadcastParallel, msg)
 			} else {
-				go func(msg *pb.Message, room *Room) {
-					var bullet = pb.Bullet{
-						Id:       float64(time.Now().UnixNano()),
-						Position: room.player[*msg.Id].Position,
-						Rotation: room.player[*msg.Id].Rotation,
-					}
-					msg.Payload.Bullet = &bullet
-					go room.broadcastParallel(msg)
-					go room.handleBulletMovement(&bullet, msg.Id)
-				}(msg, room)
+				go room.timed(room.fireWeapon, msg)
 			}
+		case WEAPON:
+			go room.timed(room.switchWeapon, msg)
+		case RELOAD:
+			go room.timed(room.reloadWeapon, msg)
+		case ADD_BOT:
+			go room.timed(room.addBot, msg)
+		case REMOVE_BOT:
+			go room.timed(room.removeBot, msg)
 		case KILLS:
-			go func(msg *pb.Message, room *Room) {
-				room.mu.RLock()
-				defer room.mu.RUnlock()
-				room.player[*msg.Id].mu.Lock()
-				defer room.player[*msg.Id].mu.Unlock()
-				room.player[*msg.Id].Kills++
-				msg.Payload = &pb.Payload{Kills: &room.player[*msg.Id].Kills}
-				data, err := proto.Marshal(msg)
-				if err != nil {
-					return
-				}
-				_ = wsutil.WriteServerBinary(*room.player[*msg.Id].Conn, data)
-			}(msg, room)
+			go room.timed(room.countKill, msg)
 		default:
 			// doing nothing yet
 		}
 	}
 }
 
+// timed runs the handler of one message from the room loop and records how
+// long it took.
+func (room *Room) timed(handle func(*pb.Message), msg *pb.Message) {
+	var start = time.Now()
+	handle(msg)
+	metrics.tickDuration.since(start)
+}
+
+func (room *Room) setReady(msg *pb.Message) {
+	if msg.Payload == nil || msg.Payload.IsReady == nil {
+		return
+	}
+	room.broadcastParallel(msg)
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+	var player = room.player[*msg.Id]
+	if player == nil {
+		return
+	}
+	player.mu.Lock()
+	player.IsReady = *msg.Payload.IsReady
+	player.mu.Unlock()
+}
+
+// countKill credits player msg.Id with a kill and tells them their count.
+func (room *Room) countKill(msg *pb.Message) {
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+	var player = room.player[*msg.Id]
+	if player == nil {
+		return
+	}
+	player.mu.Lock()
+	player.Kills++
+	var kills = player.Kills
+	player.mu.Unlock()
+	// the team total reads every player, so the killer is unlocked
+	room.checkKillLimit(player)
+
+	player.mu.Lock()
+	defer player.mu.Unlock()
+	msg.Payload = &pb.Payload{Kills: &kills}
+	room.recorder.record(msg, false)
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		room.playerLogger(*msg.Id).Error("failed to marshal message", "event", msg.Event, "err", err)
+		return
+	}
+	room.sendSpectators(msg.Event, data)
+	player.send(msg.Event, data)
+}
+
 func (room *Room) startGame(msg *pb.Message) {
-	go room.broadcastParallel(msg)
 	room.mu.Lock()
+	if room.IsGameStarted {
+		room.mu.Unlock()
+		return
+	}
 	room.IsGameStarted = true
 	room.mu.Unlock()
 	room.mu.RLock()
+	room.recorder.begin(room)
+	room.mu.RUnlock()
+	go room.broadcastParallel(msg)
+	room.logger().Info("game started")
+	room.mu.RLock()
 	defer room.mu.RUnlock()
 
-	data := pb.Message{
-		Event: SPAWN,
-		Payload: &pb.Payload{
-			Players: []*pb.Player{},
-		},
-	}
-
 	for _, player := range room.player {
 		if player != nil {
 			player.mu.RLock()
-			data.Payload.Players = append(data.Payload.Players, player.toProto())
+			room.index.update(player.Id, player.Position)
 			player.mu.RUnlock()
+			room.markSeen(player.Id)
+			if player.isBot() {
+				go room.runBot(player.Id)
+			}
 		}
 	}
 
-	data.Payload.Map = gameMap
-	go room.broadcastParallel(&data)
+	if room.isKingOfTheHill() {
+		go room.runControlZone()
+	}
+	if room.safeZone != nil {
+		room.safeZone.mu.Lock()
+		room.safeZone.schedule(time.Now())
+		room.safeZone.mu.Unlock()
+		go room.runSafeZone()
+	}
+	go room.runPowerUps()
+	go room.broadcastParallel(&pb.Message{Event: SPAWN, Payload: room.worldPayload()})
+	room.startMatchTimer()
 }
 
 func (room *Room) broadcastParallel(msg *pb.Message) {
+	var start = time.Now()
 	data, err := proto.Marshal(msg)
 	if err != nil {
+		room.logger().Error("failed to marshal message", "event", msg.Event, "err", err)
 		return
 	}
+	room.recorder.record(msg, false)
 	room.mu.RLock()
 	defer room.mu.RUnlock()
+	room.sendSpectators(msg.Event, data)
 	for _, player := range room.player {
-		go func(player *Player) {
-			if player == nil {
-				return
-			}
-			player.mu.Lock()
-			defer player.mu.Unlock()
-			if player.Conn == nil {
-				return
-			}
-			_ = wsutil.WriteServerBinary(*player.Conn, data)
-		}(player)
+		if player != nil {
+			player.mu.RLock()
+			player.send(msg.Event, data)
+			player.mu.RUnlock()
+		}
 	}
+	metrics.broadcastLatency.since(start)
 }
 
 func (room *Room) kickPlayer(msg *pb.Message) {
@@ -158,20 +269,14 @@ func (room *Room) kickPlayer(msg *pb.Message) {
 					go room.removePlayer(player.Id)
 				}
 			}
-			room.broadcast <- &pb.Message{
+			room.post(&pb.Message{
 				Event: DELETE,
-			}
+			})
 		}
 	}()
 	go room.broadcastParallel(msg)
-	var roomSize uint8
 	room.mu.RLock()
-	for _, player := range room.player {
-		if player != nil && player.Id != *msg.Id {
-			roomSize++
-		}
-	}
-	if roomSize <= 1 && room.IsGameStarted {
+	if room.IsGameStarted && (room.remainingSides(*msg.Id) <= 1 || !room.hasHumans(*msg.Id)) {
 		go room.broadcastGameOver()
 	}
 	room.mu.RUnlock()
@@ -187,49 +292,124 @@ func (room *Room) removePlayer(ID int32) {
 	data, _ := proto.Marshal(&pb.Message{
 		Id:      &ID,
 		Event:   KICK,
-		Payload: &pb.Payload{Kills: &room.player[ID].Kills},
+		Payload: &pb.Payload{Kills: &room.player[ID].Kills, Deaths: &room.player[ID].Deaths},
 	})
-	wsutil.WriteServerBinary(*room.player[ID].Conn, data)
-	_ = (*room.player[ID].Conn).Close()
-	room.player[ID].Conn = nil
+	room.dropFlag(ID, room.player[ID].Position)
+	// players kicked from the lobby may never have opened a socket
+	if room.player[ID].outbox != nil {
+		room.player[ID].send(KICK, data)
+		room.player[ID].outbox.finish()
+		room.player[ID].Conn, room.player[ID].outbox = nil, nil
+	}
 	room.player[ID].mu.Unlock()
 	room.player[ID] = nil
+	room.index.remove(ID)
+	room.unfollow(ID)
+	room.playerLogger(ID).Info("player kicked")
 }
 
 func (room *Room) broadcastGameOver() {
+	room.mu.Lock()
+	if room.isOver {
+		room.mu.Unlock()
+		return
+	}
+	room.isOver = true
+	room.mu.Unlock()
+
+	room.logger().Info("game over")
 	room.mu.RLock()
 	defer room.mu.RUnlock()
+
+	// the final scoreboard is written before anyone is removed so it can't
+	// race the KICK that closes the connection
+	var result = pb.Message{
+		Event:   GAME_OVER,
+		Payload: &pb.Payload{Players: []*pb.Player{}, Settings: room.settings},
+	}
+	if room.settings.Teams {
+		result.Payload.TeamScores = room.teamScores()
+	}
+	for _, player := range room.player {
+		if player != nil {
+			player.mu.RLock()
+			result.Payload.Players = append(result.Payload.Players, player.toProto())
+			player.mu.RUnlock()
+		}
+	}
+	room.recorder.record(&result, false)
+	if data, err := proto.Marshal(&result); err == nil {
+		room.sendSpectators(GAME_OVER, data)
+		for _, player := range room.player {
+			if player == nil {
+				continue
+			}
+			player.mu.RLock()
+			player.send(GAME_OVER, data)
+			player.mu.RUnlock()
+		}
+	}
+
 	for _, player := range room.player {
 		if player != nil {
 			go room.removePlayer(player.Id)
 		}
 	}
-	room.broadcast <- &pb.Message{
+	room.post(&pb.Message{
 		Event: DELETE,
+	})
+}
+
+// post hands msg to the room's event loop, or drops it once the loop has
+// returned and closed done.
+func (room *Room) post(msg *pb.Message) bool {
+	select {
+	case room.broadcast <- msg:
+		return true
+	case <-room.done:
+		return false
 	}
 }
 
 func (room *Room) broadcastMove(msg *pb.Message) {
-	var wg sync.WaitGroup
+	if msg.Payload == nil || msg.Payload.Position == nil {
+		return
+	}
 	var movement = msg.Payload.Position
-	var angle = normalizeMovement(movement)
-	var newPosition pb.Position
-	var isCollided bool
-	var inGrass bool
+	var angle = sim.NormalizeMovement(movement)
+	var step pb.Position
 
 	room.mu.RLock()
 	defer room.mu.RUnlock()
+	// the player may have left or been eliminated since they sent it
+	var player = room.player[*msg.Id]
+	if player == nil {
+		return
+	}
 
-	room.player[*msg.Id].mu.RLock()
-	var currentPosition = room.player[*msg.Id].Position
-	room.player[*msg.Id].mu.RUnlock()
+	player.mu.RLock()
+	var currentPosition = player.Position
+	var isDead = player.IsDead
+	var team = player.Team
+	var isBoosted = player.hasEffect(POWERUP_SPEED, time.Now())
+	player.mu.RUnlock()
 
-	calculateNewPosition(currentPosition, &angle, PLAYER_SPEED, &newPosition)
+	if isDead {
+		return
+	}
 
-	wg.Add(2)
-	go checkInGrass(&newPosition, &inGrass, &wg)
-	go checkCollision(PLAYER_SIZE, &newPosition, &isCollided, &wg)
+	var speed float64 = sim.PLAYER_SPEED
+	if isBoosted {
+		speed *= SPEED_BOOST_FACTOR
+	}
+	var isCarrier = room.isCaptureTheFlag() && room.ctf.carrying(*msg.Id)
+	if isCarrier {
+		speed *= CARRIER_SPEED_FACTOR
+	}
 
+	sim.CalculateNewPosition(&pb.Position{}, &angle, speed, &step)
+	var newPosition = sim.SlideMove(room.obstacles, sim.PLAYER_SIZE, currentPosition, &step, room.bodiesNear(*msg.Id, currentPosition))
+	var inGrass = sim.IsInGrass(room.gameMap, newPosition)
 	msg.Payload.InGrass = &inGrass
 
 	var Rotaion float64
@@ -238,18 +418,20 @@ func (room *Room) broadcastMove(msg *pb.Message) {
 		msg.Payload.Rotation = &Rotaion
 	}
 
-	wg.Wait()
-
-	if !isCollided {
-		msg.Payload.Position = &newPosition
-	} else {
-		msg.Payload.Position = currentPosition
+	// flag carriers can't hide
+	if isCarrier {
+		inGrass = false
 	}
-	go room.broadcastParallel(msg)
+	msg.Payload.Position = newPosition
 
-	room.player[*msg.Id].mu.Lock()
-	room.player[*msg.Id].Position = msg.Payload.Position
-	room.player[*msg.Id].Rotation = Rotaion
-	room.player[*msg.Id].InGrass = inGrass
-	room.player[*msg.Id].mu.Unlock()
-}
\ No newline at end of file
+	player.mu.Lock()
+	player.Position = msg.Payload.Position
+	player.Rotation = Rotaion
+	player.InGrass = inGrass
+	room.index.update(*msg.Id, msg.Payload.Position)
+	room.collectPickups(player)
+	player.mu.Unlock()
+	room.broadcastVisible(msg)
+
+	room.updateFlags(*msg.Id, team, msg.Payload.Position)
+}
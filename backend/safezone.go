@@ -0,0 +1,180 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+const SAFE_ZONE_TICK = time.Second
+
+type safeZonePhase struct {
+	wait   time.Duration // before the zone starts shrinking
+	shrink time.Duration
+	radius float64 // fraction of the starting radius
+	damage int32   // per tick to players outside the zone
+}
+
+// SAFE_ZONE_PHASES close the zone completely after about five minutes.
+var SAFE_ZONE_PHASES = []safeZonePhase{
+	{wait: 60 * time.Second, shrink: 30 * time.Second, radius: 0.6, damage: 1},
+	{wait: 45 * time.Second, shrink: 30 * time.Second, radius: 0.35, damage: 2},
+	{wait: 30 * time.Second, shrink: 20 * time.Second, radius: 0.15, damage: 5},
+	{wait: 20 * time.Second, shrink: 20 * time.Second, radius: 0, damage: 10},
+}
+
+// safeZoneState is the battle-royale circle of last man standing. Like the
+// other mode states, its lock is taken after room.mu and player.mu.
+type safeZoneState struct {
+	phase         int
+	startRadius   float64
+	center        *pb.Position
+	radius        float64
+	fromCenter    *pb.Position
+	fromRadius    float64
+	nextCenter    *pb.Position
+	nextRadius    float64
+	shrinkStartAt time.Time
+	shrinkEndAt   time.Time
+	mu            sync.Mutex
+}
+
+// newSafeZoneState starts with a circle around the whole map.
+func newSafeZoneState(gameMap *pb.GameMap, obstacles *sim.ObstacleIndex) *safeZoneState {
+	var state = safeZoneState{
+		center: &pb.Position{X: float64(gameMap.Width) / 2, Y: float64(gameMap.Height) / 2},
+		radius: math.Hypot(float64(gameMap.Width), float64(gameMap.Height)) / 2,
+	}
+	state.startRadius = state.radius
+	state.planPhase(obstacles, time.Now())
+	return &state
+}
+
+func (state *safeZoneState) current() safeZonePhase {
+	return SAFE_ZONE_PHASES[min(state.phase, len(SAFE_ZONE_PHASES)-1)]
+}
+
+// planPhase picks the circle the current phase shrinks to: a random point
+// a player can stand on, chosen so the new circle lies inside the old one.
+// The caller holds state.mu.
+func (state *safeZoneState) planPhase(obstacles *sim.ObstacleIndex, now time.Time) {
+	state.fromCenter, state.fromRadius = state.center, state.radius
+	if state.phase >= len(SAFE_ZONE_PHASES) {
+		state.nextCenter, state.nextRadius = state.center, state.radius
+		return
+	}
+
+	state.nextRadius = state.startRadius * state.current().radius
+	state.nextCenter = state.center
+	var reach = state.radius - state.nextRadius
+	for i := 0; i < sim.SPAWN_SAMPLES; i++ {
+		var angle = rand.Float64() * 2 * math.Pi
+		var distance = reach * math.Sqrt(rand.Float64())
+		candidate := &pb.Position{
+			X: state.center.X + math.Cos(angle)*distance,
+			Y: state.center.Y + math.Sin(angle)*distance,
+		}
+		if !obstacles.IsBlocked(sim.PLAYER_SIZE, candidate) {
+			state.nextCenter = candidate
+			break
+		}
+	}
+	state.schedule(now)
+}
+
+// schedule times the current phase's shrink from now. The caller holds
+// state.mu.
+func (state *safeZoneState) schedule(now time.Time) {
+	var phase = state.current()
+	state.shrinkStartAt = now.Add(phase.wait)
+	state.shrinkEndAt = state.shrinkStartAt.Add(phase.shrink)
+}
+
+// update moves the circle towards the phase's target and starts the next
+// phase once it gets there. The caller holds state.mu.
+func (state *safeZoneState) update(obstacles *sim.ObstacleIndex, now time.Time) {
+	if state.phase >= len(SAFE_ZONE_PHASES) || now.Before(state.shrinkStartAt) {
+		return
+	}
+	if now.Before(state.shrinkEndAt) {
+		var progress = float64(now.Sub(state.shrinkStartAt)) / float64(state.shrinkEndAt.Sub(state.shrinkStartAt))
+		state.center = &pb.Position{
+			X: state.fromCenter.X + (state.nextCenter.X-state.fromCenter.X)*progress,
+			Y: state.fromCenter.Y + (state.nextCenter.Y-state.fromCenter.Y)*progress,
+		}
+		state.radius = state.fromRadius + (state.nextRadius-state.fromRadius)*progress
+		return
+	}
+	state.center, state.radius = state.nextCenter, state.nextRadius
+	state.phase++
+	state.planPhase(obstacles, now)
+}
+
+// toProto describes the zone. The caller holds state.mu.
+func (state *safeZoneState) toProto(now time.Time) *pb.SafeZone {
+	var zone = pb.SafeZone{
+		Center:     state.center,
+		Radius:     state.radius,
+		NextCenter: state.nextCenter,
+		NextRadius: state.nextRadius,
+		Phase:      uint32(min(state.phase, len(SAFE_ZONE_PHASES)-1) + 1),
+		Damage:     state.current().damage,
+	}
+	if state.phase < len(SAFE_ZONE_PHASES) {
+		if now.Before(state.shrinkStartAt) {
+			zone.ShrinksIn = uint32(math.Ceil(state.shrinkStartAt.Sub(now).Seconds()))
+		} else {
+			zone.Shrinking = true
+		}
+	}
+	return &zone
+}
+
+// runSafeZone shrinks the zone and damages everyone outside it every
+// SAFE_ZONE_TICK until the match is over. startGame schedules the first
+// phase before calling it.
+func (room *Room) runSafeZone() {
+	ticker := time.NewTicker(SAFE_ZONE_TICK)
+	defer ticker.Stop()
+	for range ticker.C {
+		room.mu.RLock()
+		if room.isOver {
+			room.mu.RUnlock()
+			return
+		}
+		room.tickSafeZone()
+		room.mu.RUnlock()
+	}
+}
+
+// tickSafeZone runs one shrink and damage step. The caller holds room.mu.
+func (room *Room) tickSafeZone() {
+	var state = room.safeZone
+	var now = time.Now()
+
+	state.mu.Lock()
+	state.update(room.obstacles, now)
+	var zone = state.toProto(now)
+	state.mu.Unlock()
+
+	go room.broadcastParallel(&pb.Message{
+		Event:   SAFE_ZONE,
+		Payload: &pb.Payload{SafeZone: zone},
+	})
+
+	for _, player := range room.player {
+		if player == nil {
+			continue
+		}
+		player.mu.Lock()
+		if !player.IsDead && player.Position != nil &&
+			!sim.InsideCircle(player.Position, zone.Center.X, zone.Center.Y, zone.Radius) {
+			room.damagePlayer(player, zone.Damage, nil, sim.TEAM_NONE)
+		}
+		player.mu.Unlock()
+	}
+}
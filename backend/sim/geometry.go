@@ -0,0 +1,74 @@
+package sim
+
+import (
+	"math"
+
+	pb "battle-arena/message"
+)
+
+const (
+	PLAYER_SPEED  = 4
+	BULLET_SPEED  = 7
+	PLAYER_SIZE   = 20
+	BULLET_SIZE   = 4
+	BULLET_DAMAGE = 10
+)
+
+func CalculateNewPosition(currentPosition *pb.Position, angle *float64, speed float64, newPosition *pb.Position) {
+	newPosition.X = currentPosition.X + math.Cos(*angle)*speed
+	newPosition.Y = currentPosition.Y + math.Sin(*angle)*speed
+}
+
+func NormalizeMovement(movement *pb.Position) float64 {
+	var magnitude = math.Sqrt(movement.X*movement.X + movement.Y*movement.Y)
+	if magnitude == 0 {
+		return math.Atan2(movement.Y, movement.X)
+	}
+	return math.Atan2(movement.Y/magnitude, movement.X/magnitude)
+}
+
+func IsInGrass(gameMap *pb.GameMap, position *pb.Position) bool {
+	for _, grass := range gameMap.GrassPatches {
+		if InsideCircle(position, float64(grass.X), float64(grass.Y), float64(grass.Radius)) {
+			return true
+		}
+	}
+	return false
+}
+
+func InsideCircle(position *pb.Position, x, y, radius float64) bool {
+	return math.Hypot(position.X-x, position.Y-y) < radius
+}
+
+func IsOutOfBounds(gameMap *pb.GameMap, size float64, position *pb.Position) bool {
+	return position.X-size < 0 ||
+		position.X+size > float64(gameMap.Width) ||
+		position.Y-size < 0 ||
+		position.Y+size > float64(gameMap.Height)
+}
+
+// OverlapsObstacle reports whether a circle of radius size at position
+// overlaps the rectangle, or for a point, whether it's inside.
+func OverlapsObstacle(obstacle *pb.Obstacle, size float64, position *pb.Position) bool {
+	var closestX, closestY = closestPoint(obstacle, position)
+	if closestX == position.X && closestY == position.Y {
+		// the center is inside or on the edge, which only a point can touch
+		// without overlapping
+		if size > 0 {
+			return true
+		}
+		return position.X > float64(obstacle.X) && position.X < float64(obstacle.X+obstacle.Width) &&
+			position.Y > float64(obstacle.Y) && position.Y < float64(obstacle.Y+obstacle.Height)
+	}
+	return math.Hypot(position.X-closestX, position.Y-closestY) < size
+}
+
+// closestPoint returns the point of the rectangle nearest to position.
+func closestPoint(obstacle *pb.Obstacle, position *pb.Position) (float64, float64) {
+	return min(max(position.X, float64(obstacle.X)), float64(obstacle.X+obstacle.Width)),
+		min(max(position.Y, float64(obstacle.Y)), float64(obstacle.Y+obstacle.Height))
+}
+
+func OverlapsCircle(circle *pb.CircleObstacle, size float64, position *pb.Position) bool {
+	return math.Hypot(position.X-float64(circle.X), position.Y-float64(circle.Y)) < float64(circle.Radius)+size
+}
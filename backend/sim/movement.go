@@ -0,0 +1,111 @@
+package sim
+
+import (
+	"math"
+
+	pb "battle-arena/message"
+)
+
+const (
+	// contacts a move is slid along before it gives up
+	MAX_SLIDE_ITERATIONS = 4
+	// gap left between a player and what they slid along
+	SLIDE_EPSILON = 1e-6
+)
+
+// contact is how far a circle overlaps something and the direction that
+// pushes it back out.
+type contact struct {
+	normalX float64
+	normalY float64
+	depth   float64
+}
+
+// keep replaces the contact with a deeper one.
+func (deepest *contact) keep(normalX, normalY, depth float64) {
+	if depth > deepest.depth {
+		*deepest = contact{normalX: normalX, normalY: normalY, depth: depth}
+	}
+}
+
+// circleContact is the overlap of a circle of radius size at position with
+// a circle of radius radius at (x, y).
+func circleContact(position *pb.Position, size, x, y, radius float64) (float64, float64, float64) {
+	var dx, dy = position.X - x, position.Y - y
+	var distance = math.Hypot(dx, dy)
+	if distance == 0 {
+		return 1, 0, radius + size
+	}
+	return dx / distance, dy / distance, radius + size - distance
+}
+
+// rectContact is the overlap of a circle of radius size at position with a
+// rectangle. A center inside the rectangle is pushed out the nearest side.
+func rectContact(position *pb.Position, size float64, obstacle *pb.Obstacle) (float64, float64, float64) {
+	var closestX, closestY = closestPoint(obstacle, position)
+	var dx, dy = position.X - closestX, position.Y - closestY
+	if distance := math.Hypot(dx, dy); distance > 0 {
+		return dx / distance, dy / distance, size - distance
+	}
+
+	var sides = [4]contact{
+		{normalX: -1, depth: position.X - float64(obstacle.X)},
+		{normalX: 1, depth: float64(obstacle.X+obstacle.Width) - position.X},
+		{normalY: -1, depth: position.Y - float64(obstacle.Y)},
+		{normalY: 1, depth: float64(obstacle.Y+obstacle.Height) - position.Y},
+	}
+	var nearest = sides[0]
+	for _, side := range sides[1:] {
+		if side.depth < nearest.depth {
+			nearest = side
+		}
+	}
+	return nearest.normalX, nearest.normalY, nearest.depth + size
+}
+
+// deepestContact finds the deepest overlap of a circle of radius size at
+// position with the map edges, the obstacles and other players' bodies.
+func deepestContact(index *ObstacleIndex, size float64, position *pb.Position, bodies []*pb.Position) contact {
+	var gameMap = index.gameMap
+	var deepest contact
+	deepest.keep(1, 0, size-position.X)
+	deepest.keep(-1, 0, position.X+size-float64(gameMap.Width))
+	deepest.keep(0, 1, size-position.Y)
+	deepest.keep(0, -1, position.Y+size-float64(gameMap.Height))
+
+	var minX, minY, maxX, maxY = position.X - size, position.Y - size, position.X + size, position.Y + size
+	index.rects.Query(minX, minY, maxX, maxY, func(obstacle *pb.Obstacle) bool {
+		deepest.keep(rectContact(position, size, obstacle))
+		return false
+	})
+	index.circles.Query(minX, minY, maxX, maxY, func(circle *pb.CircleObstacle) bool {
+		deepest.keep(circleContact(position, size, float64(circle.X), float64(circle.Y), float64(circle.Radius)))
+		return false
+	})
+	for _, body := range bodies {
+		deepest.keep(circleContact(position, size, body.X, body.Y, PLAYER_SIZE))
+	}
+	return deepest
+}
+
+// SlideMove moves a circle of radius size from by movement and slides it
+// along whatever it runs into: the part of the move going into an obstacle,
+// the map edge or another player's body is dropped and the rest is kept.
+// It stays at from when the move can't be resolved, like in a tight corner.
+func SlideMove(obstacles *ObstacleIndex, size float64, from, movement *pb.Position, bodies []*pb.Position) *pb.Position {
+	var position = &pb.Position{X: from.X + movement.X, Y: from.Y + movement.Y}
+	for i := 0; i < MAX_SLIDE_ITERATIONS; i++ {
+		var deepest = deepestContact(obstacles, size, position, bodies)
+		if deepest.depth <= 0 {
+			return position
+		}
+		position = &pb.Position{
+			X: position.X + deepest.normalX*(deepest.depth+SLIDE_EPSILON),
+			Y: position.Y + deepest.normalY*(deepest.depth+SLIDE_EPSILON),
+		}
+	}
+	if deepestContact(obstacles, size, position, bodies).depth > 0 {
+		return from
+	}
+	return position
+}
@@ -0,0 +1,56 @@
+package sim
+
+import (
+	"math"
+	"testing"
+
+	pb "battle-arena/message"
+)
+
+func TestSlideMove(t *testing.T) {
+	var gameMap = &pb.GameMap{
+		Width:           400,
+		Height:          400,
+		Obstacles:       []*pb.Obstacle{{X: 200, Y: 0, Width: 50, Height: 300}},
+		CircleObstacles: []*pb.CircleObstacle{{X: 100, Y: 300, Radius: 30}},
+	}
+
+	var tests = []struct {
+		name           string
+		from, movement *pb.Position
+		bodies         []*pb.Position
+		want           *pb.Position
+	}{
+		{"free move", &pb.Position{X: 100, Y: 100}, &pb.Position{X: 3, Y: 4}, nil, &pb.Position{X: 103, Y: 104}},
+		{"head on into a wall", &pb.Position{X: 180, Y: 100}, &pb.Position{X: 4, Y: 0}, nil, &pb.Position{X: 180, Y: 100}},
+		{"slides along a wall", &pb.Position{X: 180, Y: 100}, &pb.Position{X: 3, Y: 3}, nil, &pb.Position{X: 180, Y: 103}},
+		{"slides along the map edge", &pb.Position{X: 20, Y: 100}, &pb.Position{X: -3, Y: -3}, nil, &pb.Position{X: 20, Y: 97}},
+		{"passes a rounded corner", &pb.Position{X: 180, Y: 315}, &pb.Position{X: 4, Y: 0}, nil, &pb.Position{X: 184, Y: 315}},
+		{"blocked by a body", &pb.Position{X: 100, Y: 100}, &pb.Position{X: 4, Y: 0}, []*pb.Position{{X: 140, Y: 100}}, &pb.Position{X: 100, Y: 100}},
+		{"slides around a body", &pb.Position{X: 100, Y: 100}, &pb.Position{X: 3, Y: 3}, []*pb.Position{{X: 140, Y: 100}}, nil},
+		{"slides around a pillar", &pb.Position{X: 100, Y: 250}, &pb.Position{X: 3, Y: 3}, nil, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got = SlideMove(NewObstacleIndex(gameMap), PLAYER_SIZE, test.from, test.movement, test.bodies)
+			if NewObstacleIndex(gameMap).IsBlocked(PLAYER_SIZE, got) {
+				t.Fatalf("ended inside an obstacle at %v", got)
+			}
+			for _, body := range test.bodies {
+				if math.Hypot(got.X-body.X, got.Y-body.Y) < 2*PLAYER_SIZE {
+					t.Fatalf("ended inside a body at %v", got)
+				}
+			}
+			if test.want == nil {
+				// curved surfaces: the player moves on without stalling
+				if math.Hypot(got.X-test.from.X, got.Y-test.from.Y) < 1 {
+					t.Fatalf("stuck at %v", got)
+				}
+				return
+			}
+			if math.Abs(got.X-test.want.X) > 1e-3 || math.Abs(got.Y-test.want.Y) > 1e-3 {
+				t.Fatalf("moved to %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package sim
+
+// Rand is a splitmix64 generator. It's a plain value, so a State carries
+// where it is in its sequence and copying the State copies that too.
+type Rand struct {
+	state uint64
+}
+
+func NewRand(seed uint64) Rand {
+	return Rand{state: seed}
+}
+
+func (rng *Rand) Uint64() uint64 {
+	rng.state += 0x9e3779b97f4a7c15
+	var z = rng.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Float64 returns a number in [0, 1).
+func (rng *Rand) Float64() float64 {
+	return float64(rng.Uint64()>>11) / (1 << 53)
+}
+
+// Intn returns a number in [0, n). It panics if n <= 0.
+func (rng *Rand) Intn(n int) int {
+	if n <= 0 {
+		panic("sim: invalid argument to Intn")
+	}
+	return int(rng.Uint64() % uint64(n))
+}
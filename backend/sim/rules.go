@@ -0,0 +1,77 @@
+package sim
+
+import (
+	"math"
+	"time"
+
+	pb "battle-arena/message"
+)
+
+// Game modes
+const (
+	MODE_LAST_MAN_STANDING = "last-man-standing"
+	MODE_DEATHMATCH        = "deathmatch"
+	MODE_CAPTURE_THE_FLAG  = "capture-the-flag"
+	MODE_KING_OF_THE_HILL  = "king-of-the-hill"
+)
+
+// Teams
+const (
+	TEAM_NONE  = 0
+	TEAM_RED   = 1
+	TEAM_BLUE  = 2
+	TEAM_COUNT = 2
+)
+
+const PLAYER_MAX_HEALTH = 100
+
+func IsValidTeam(team int32) bool {
+	return team >= TEAM_RED && team <= TEAM_COUNT
+}
+
+// HasRespawns reports whether dead players come back instead of being out,
+// which is every mode but last man standing.
+func HasRespawns(settings *pb.GameSettings) bool {
+	return settings.Mode != MODE_LAST_MAN_STANDING
+}
+
+// IsFriendlyFire reports whether a hit between these teams is between
+// teammates and must be ignored.
+func IsFriendlyFire(settings *pb.GameSettings, shooterTeam, targetTeam int32) bool {
+	return settings.Teams && !settings.FriendlyFire &&
+		IsValidTeam(shooterTeam) && shooterTeam == targetTeam
+}
+
+// CountsAsKill reports whether a kill scores for the killer. Team kills,
+// possible with friendly fire on, never do.
+func CountsAsKill(settings *pb.GameSettings, killerTeam, victimTeam int32) bool {
+	return !settings.Teams || killerTeam != victimTeam
+}
+
+// RespawnDelay is how long dead players wait before they come back.
+func RespawnDelay(settings *pb.GameSettings) time.Duration {
+	return time.Duration(settings.RespawnDelay) * time.Millisecond
+}
+
+// TimeLimit is how long a respawning match lasts, 0 for no limit.
+func TimeLimit(settings *pb.GameSettings) time.Duration {
+	return time.Duration(settings.TimeLimit) * time.Second
+}
+
+// ReachedKillLimit reports whether a side with kills kills wins a deathmatch.
+func ReachedKillLimit(settings *pb.GameSettings, kills int32) bool {
+	return settings.Mode == MODE_DEATHMATCH && settings.KillLimit > 0 && kills >= settings.KillLimit
+}
+
+// Touches reports whether a player at position touches something of the
+// given radius at target, like a flag or a pickup.
+func Touches(position, target *pb.Position, radius float64) bool {
+	return math.Hypot(position.X-target.X, position.Y-target.Y) < radius+PLAYER_SIZE
+}
+
+// ApplyDamage takes amount off the shield and then the health and returns
+// what's left of both.
+func ApplyDamage(health, shield, amount int32) (int32, int32) {
+	var absorbed = min(amount, shield)
+	return health - (amount - absorbed), shield - absorbed
+}
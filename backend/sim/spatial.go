@@ -0,0 +1,117 @@
+package sim
+
+import (
+	"math"
+	"slices"
+
+	pb "battle-arena/message"
+)
+
+// side of a spatial hash cell, around the size of an average obstacle
+const SPATIAL_CELL_SIZE = 128
+
+// SpatialHash is a uniform grid broad phase. An item is filed under every
+// cell its bounding box overlaps, so a query only looks at the items in the
+// cells around it. Items spanning several cells can be visited more than
+// once.
+type SpatialHash[T comparable] struct {
+	columns int
+	rows    int
+	cells   [][]T
+}
+
+func NewSpatialHash[T comparable](width, height uint32) *SpatialHash[T] {
+	var columns = max(1, int(math.Ceil(float64(width)/SPATIAL_CELL_SIZE)))
+	var rows = max(1, int(math.Ceil(float64(height)/SPATIAL_CELL_SIZE)))
+	return &SpatialHash[T]{columns: columns, rows: rows, cells: make([][]T, columns*rows)}
+}
+
+// cellRange returns the cells a box overlaps, clamped to the grid.
+func (hash *SpatialHash[T]) cellRange(minX, minY, maxX, maxY float64) (int, int, int, int) {
+	var clamp = func(value float64, limit int) int {
+		return min(max(int(math.Floor(value/SPATIAL_CELL_SIZE)), 0), limit-1)
+	}
+	return clamp(minX, hash.columns), clamp(minY, hash.rows), clamp(maxX, hash.columns), clamp(maxY, hash.rows)
+}
+
+func (hash *SpatialHash[T]) Insert(item T, minX, minY, maxX, maxY float64) {
+	var fromColumn, fromRow, toColumn, toRow = hash.cellRange(minX, minY, maxX, maxY)
+	for row := fromRow; row <= toRow; row++ {
+		for column := fromColumn; column <= toColumn; column++ {
+			cell := row*hash.columns + column
+			hash.cells[cell] = append(hash.cells[cell], item)
+		}
+	}
+}
+
+// Remove takes an item out of the cells of the box it was inserted with.
+func (hash *SpatialHash[T]) Remove(item T, minX, minY, maxX, maxY float64) {
+	var fromColumn, fromRow, toColumn, toRow = hash.cellRange(minX, minY, maxX, maxY)
+	for row := fromRow; row <= toRow; row++ {
+		for column := fromColumn; column <= toColumn; column++ {
+			cell := row*hash.columns + column
+			hash.cells[cell] = slices.DeleteFunc(hash.cells[cell], func(other T) bool { return other == item })
+		}
+	}
+}
+
+// Query calls visit with the items filed in the cells a box overlaps until
+// visit returns true, and reports whether it did.
+func (hash *SpatialHash[T]) Query(minX, minY, maxX, maxY float64, visit func(T) bool) bool {
+	var fromColumn, fromRow, toColumn, toRow = hash.cellRange(minX, minY, maxX, maxY)
+	for row := fromRow; row <= toRow; row++ {
+		for column := fromColumn; column <= toColumn; column++ {
+			for _, item := range hash.cells[row*hash.columns+column] {
+				if visit(item) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// ObstacleIndex files a map's obstacles in spatial hashes.
+type ObstacleIndex struct {
+	gameMap *pb.GameMap
+	rects   *SpatialHash[*pb.Obstacle]
+	circles *SpatialHash[*pb.CircleObstacle]
+}
+
+func NewObstacleIndex(gameMap *pb.GameMap) *ObstacleIndex {
+	var index = ObstacleIndex{
+		gameMap: gameMap,
+		rects:   NewSpatialHash[*pb.Obstacle](gameMap.Width, gameMap.Height),
+		circles: NewSpatialHash[*pb.CircleObstacle](gameMap.Width, gameMap.Height),
+	}
+	for _, obstacle := range gameMap.Obstacles {
+		index.rects.Insert(obstacle, float64(obstacle.X), float64(obstacle.Y),
+			float64(obstacle.X+obstacle.Width), float64(obstacle.Y+obstacle.Height))
+	}
+	for _, circle := range gameMap.CircleObstacles {
+		var x, y, radius = float64(circle.X), float64(circle.Y), float64(circle.Radius)
+		index.circles.Insert(circle, x-radius, y-radius, x+radius, y+radius)
+	}
+	return &index
+}
+
+// HasLineOfSight reports whether no obstacle crosses the straight line
+// between two points.
+func (index *ObstacleIndex) HasLineOfSight(from, to *pb.Position) bool {
+	_, blocked := index.Sweep(0, from, to)
+	return !blocked
+}
+
+// IsBlocked reports whether a circle of radius size at position leaves the
+// map or touches an obstacle.
+func (index *ObstacleIndex) IsBlocked(size float64, position *pb.Position) bool {
+	if IsOutOfBounds(index.gameMap, size, position) {
+		return true
+	}
+	var minX, minY, maxX, maxY = position.X - size, position.Y - size, position.X + size, position.Y + size
+	return index.rects.Query(minX, minY, maxX, maxY, func(obstacle *pb.Obstacle) bool {
+		return OverlapsObstacle(obstacle, size, position)
+	}) || index.circles.Query(minX, minY, maxX, maxY, func(circle *pb.CircleObstacle) bool {
+		return OverlapsCircle(circle, size, position)
+	})
+}
@@ -0,0 +1,124 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	pb "battle-arena/message"
+)
+
+// isBlockedLinear is the scan over every obstacle that the index replaces.
+func isBlockedLinear(gameMap *pb.GameMap, size float64, position *pb.Position) bool {
+	if IsOutOfBounds(gameMap, size, position) {
+		return true
+	}
+	for _, obstacle := range gameMap.Obstacles {
+		if OverlapsObstacle(obstacle, size, position) {
+			return true
+		}
+	}
+	for _, circle := range gameMap.CircleObstacles {
+		if OverlapsCircle(circle, size, position) {
+			return true
+		}
+	}
+	return false
+}
+
+// clutteredMap scatters count obstacles over a square map, half of them
+// circles, without caring whether it stays playable.
+func clutteredMap(size uint32, count int) *pb.GameMap {
+	var rng = rand.New(rand.NewSource(int64(count)))
+	var gameMap = &pb.GameMap{Width: size, Height: size}
+	for i := 0; i < count; i++ {
+		if i%2 == 0 {
+			width, height := uint32(20+rng.Intn(100)), uint32(20+rng.Intn(100))
+			gameMap.Obstacles = append(gameMap.Obstacles, &pb.Obstacle{
+				X: uint32(rng.Intn(int(size - width))), Y: uint32(rng.Intn(int(size - height))), Width: width, Height: height,
+			})
+		} else {
+			radius := uint32(10 + rng.Intn(50))
+			gameMap.CircleObstacles = append(gameMap.CircleObstacles, &pb.CircleObstacle{
+				X: radius + uint32(rng.Intn(int(size-2*radius))), Y: radius + uint32(rng.Intn(int(size-2*radius))), Radius: radius,
+			})
+		}
+	}
+	return gameMap
+}
+
+func randomPositions(size uint32, count int) []*pb.Position {
+	var rng = rand.New(rand.NewSource(1))
+	var positions = make([]*pb.Position, count)
+	for i := range positions {
+		positions[i] = &pb.Position{X: rng.Float64() * float64(size), Y: rng.Float64() * float64(size)}
+	}
+	return positions
+}
+
+func TestObstacleIndexMatchesLinearScan(t *testing.T) {
+	var maps = []*pb.GameMap{clutteredMap(1600, 40), clutteredMap(4000, 400)}
+	for _, gameMap := range maps {
+		var index = NewObstacleIndex(gameMap)
+		for _, position := range randomPositions(gameMap.Width, 5000) {
+			for _, size := range []float64{0, BULLET_SIZE, PLAYER_SIZE, 100} {
+				if got, want := index.IsBlocked(size, position), isBlockedLinear(gameMap, size, position); got != want {
+					t.Fatalf("IsBlocked(%v, %v) = %v, want %v", size, position, got, want)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkIsBlocked(b *testing.B) {
+	for _, count := range []int{5, 50, 500} {
+		var gameMap = clutteredMap(4000, count)
+		var index = NewObstacleIndex(gameMap)
+		var positions = randomPositions(gameMap.Width, 1024)
+
+		b.Run(fmt.Sprintf("linear/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				isBlockedLinear(gameMap, PLAYER_SIZE, positions[i%len(positions)])
+			}
+		})
+		b.Run(fmt.Sprintf("indexed/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				index.IsBlocked(PLAYER_SIZE, positions[i%len(positions)])
+			}
+		})
+	}
+}
+
+// BenchmarkBulletHit compares checking a bullet against every player with
+// asking the hash the room's player index keeps.
+func BenchmarkBulletHit(b *testing.B) {
+	for _, count := range []int{6, 64, 512} {
+		var players = randomPositions(4000, count)
+		var bullets = randomPositions(4000, 1024)
+		var hash = NewSpatialHash[int32](4000, 4000)
+		for ID, position := range players {
+			hash.Insert(int32(ID), position.X, position.Y, position.X, position.Y)
+		}
+
+		b.Run(fmt.Sprintf("linear/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bullet := bullets[i%len(bullets)]
+				for _, player := range players {
+					if math.Hypot(player.X-bullet.X, player.Y-bullet.Y) < PLAYER_SIZE {
+						break
+					}
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("indexed/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bullet := bullets[i%len(bullets)]
+				hash.Query(bullet.X-PLAYER_SIZE, bullet.Y-PLAYER_SIZE, bullet.X+PLAYER_SIZE, bullet.Y+PLAYER_SIZE, func(ID int32) bool {
+					player := players[ID]
+					return math.Hypot(player.X-bullet.X, player.Y-bullet.Y) < PLAYER_SIZE
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package sim
+
+import (
+	"math"
+
+	pb "battle-arena/message"
+)
+
+const SPAWN_SAMPLES = 64
+
+// PickSpawnPoint chooses where a player (re)enters the map: the map's spawn
+// point farthest from everyone else, or, when all of them are blocked or
+// taken, the best of a batch of random free positions. It never returns a
+// position that collides with an obstacle or overlaps another player.
+// random gives numbers in [0, 1).
+func PickSpawnPoint(obstacles *ObstacleIndex, others []*pb.Position, random func() float64) *pb.Position {
+	var gameMap = obstacles.gameMap
+	var candidates []*pb.Position
+	for _, spawn := range gameMap.SpawnPoints {
+		if IsSpawnFree(obstacles, spawn, others) {
+			candidates = append(candidates, spawn)
+		}
+	}
+
+	if len(candidates) == 0 {
+		for i := 0; i < SPAWN_SAMPLES; i++ {
+			sample := &pb.Position{
+				X: PLAYER_SIZE + random()*(float64(gameMap.Width)-2*PLAYER_SIZE),
+				Y: PLAYER_SIZE + random()*(float64(gameMap.Height)-2*PLAYER_SIZE),
+			}
+			if IsSpawnFree(obstacles, sample, others) {
+				candidates = append(candidates, sample)
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		// map validation guarantees at least one unblocked spawn point
+		var spawn = gameMap.SpawnPoints[int(random()*float64(len(gameMap.SpawnPoints)))]
+		return &pb.Position{X: spawn.X, Y: spawn.Y}
+	}
+
+	// shuffle so ties (e.g. an empty room) don't always pick the same spot
+	for i := len(candidates) - 1; i > 0; i-- {
+		j := int(random() * float64(i+1))
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	}
+
+	var best = candidates[0]
+	var bestDistance = -1.0
+	for _, candidate := range candidates {
+		var nearest = math.Inf(1)
+		for _, other := range others {
+			nearest = math.Min(nearest, math.Hypot(candidate.X-other.X, candidate.Y-other.Y))
+		}
+		if nearest > bestDistance {
+			best, bestDistance = candidate, nearest
+		}
+	}
+	return &pb.Position{X: best.X, Y: best.Y}
+}
+
+func IsSpawnFree(obstacles *ObstacleIndex, position *pb.Position, others []*pb.Position) bool {
+	if obstacles.IsBlocked(PLAYER_SIZE, position) {
+		return false
+	}
+	for _, other := range others {
+		if math.Hypot(position.X-other.X, position.Y-other.Y) < 2*PLAYER_SIZE {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,178 @@
+package sim
+
+import (
+	"time"
+
+	pb "battle-arena/message"
+)
+
+// TICK is how often the game advances, both for Step and for the server's
+// bullet loop.
+const TICK = 16 * time.Millisecond
+
+// Event types, the same strings the server uses for the matching messages.
+const (
+	EVENT_SHOOT     = "Shoot"
+	EVENT_HIT       = "Hit"
+	EVENT_DEATH     = "Death"
+	EVENT_KILLS     = "Kills"
+	EVENT_RESPAWN   = "Respawn"
+	EVENT_RELOAD    = "Reload"
+	EVENT_GAME_OVER = "Game Over"
+)
+
+// NO_ONE stands in for a missing player or team, like the killer of nobody's
+// damage or the winner of a draw.
+const NO_ONE = -1
+
+// Loadout is a weapon a player carries and the ammo left in it.
+type Loadout struct {
+	Weapon  string
+	Ammo    int32
+	Reserve int32
+}
+
+// Player is one player of a State. Positions are never changed in place,
+// only replaced, so states can share them.
+type Player struct {
+	ID       int32
+	Team     int32
+	Position *pb.Position
+	Rotation float64
+	Health   int32
+	Shield   int32
+	Kills    int32
+	Deaths   int32
+	IsDead   bool
+	// eliminated for good in last man standing
+	IsOut bool
+	// tick a dead player comes back at
+	RespawnAt uint64
+	Weapons   []Loadout
+	Held      int
+	// tick the held weapon's reload completes at, 0 when not reloading
+	ReloadAt uint64
+	// first tick the held weapon can fire again
+	NextShot uint64
+}
+
+type Bullet struct {
+	ID        uint64
+	Owner     int32
+	Weapon    string
+	Position  *pb.Position
+	Rotation  float64
+	Travelled float64
+}
+
+// State is everything Step needs to advance a game. Map, Obstacles and
+// Settings are shared between states and must not be changed.
+type State struct {
+	Tick       uint64
+	Map        *pb.GameMap
+	Obstacles  *ObstacleIndex
+	Settings   *pb.GameSettings
+	Players    []Player
+	Bullets    []Bullet
+	NextBullet uint64
+	IsOver     bool
+	// the winning player, or team with teams on; NO_ONE for a draw
+	Winner int32
+	RNG    Rand
+}
+
+// Input is what a player does during one tick, like the messages a client
+// sends.
+type Input struct {
+	Player int32
+	// direction to move in, nil or zero to stand still
+	Move  *pb.Position
+	Shoot bool
+	// weapon to draw before anything else, "" to keep the held one
+	Weapon string
+	Reload bool
+}
+
+// Event is something that happened during a tick. Player is who it happened
+// to; Other is the shooter of a Hit or the killer of a Death. Amount is the
+// health left after a Hit, the kills after Kills and the ammo after Shoot or
+// Reload.
+type Event struct {
+	Type     string
+	Player   int32
+	Other    int32
+	Amount   int32
+	Position *pb.Position
+}
+
+// NewState starts a game on gameMap with a player per entry of teams, using
+// their index as ID. seed decides the spawn points and weapon spread, so the
+// same seed and inputs always play out the same way.
+func NewState(gameMap *pb.GameMap, settings *pb.GameSettings, seed uint64, teams []int32) State {
+	var state = State{
+		Map:       gameMap,
+		Obstacles: NewObstacleIndex(gameMap),
+		Settings:  settings,
+		Players:   make([]Player, len(teams)),
+		Winner:    NO_ONE,
+		RNG:       NewRand(seed),
+	}
+	for ID, team := range teams {
+		state.Players[ID] = Player{ID: int32(ID), Team: team}
+		state.spawn(&state.Players[ID])
+	}
+	return state
+}
+
+// Ticks converts a duration to the number of ticks it lasts, rounding up.
+func Ticks(duration time.Duration) uint64 {
+	return uint64((duration + TICK - 1) / TICK)
+}
+
+func (state *State) clone() State {
+	var next = *state
+	next.Players = make([]Player, len(state.Players))
+	for i, player := range state.Players {
+		player.Weapons = append([]Loadout(nil), player.Weapons...)
+		next.Players[i] = player
+	}
+	next.Bullets = append([]Bullet(nil), state.Bullets...)
+	return next
+}
+
+// player returns the player with the given ID, or nil.
+func (state *State) player(ID int32) *Player {
+	if ID < 0 || int(ID) >= len(state.Players) {
+		return nil
+	}
+	return &state.Players[ID]
+}
+
+func (player *Player) isAlive() bool {
+	return !player.IsDead && !player.IsOut
+}
+
+// held returns the loadout of the weapon the player holds.
+func (player *Player) held() *Loadout {
+	return &player.Weapons[player.Held]
+}
+
+// spawn puts the player at a spawn point away from the living players with
+// full health and the starting loadout, like the server's respawn.
+func (state *State) spawn(player *Player) {
+	var others []*pb.Position
+	for _, other := range state.Players {
+		if other.ID != player.ID && other.Position != nil && other.isAlive() {
+			others = append(others, other.Position)
+		}
+	}
+	var weapon = WEAPONS[DEFAULT_WEAPON]
+	var ammo, reserve = weapon.FullLoad()
+	player.Position = PickSpawnPoint(state.Obstacles, others, state.RNG.Float64)
+	player.Health = PLAYER_MAX_HEALTH
+	player.Shield = 0
+	player.IsDead = false
+	player.Weapons = []Loadout{{Weapon: weapon.Name, Ammo: ammo, Reserve: reserve}}
+	player.Held = 0
+	player.ReloadAt = 0
+}
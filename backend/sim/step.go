@@ -0,0 +1,249 @@
+package sim
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	pb "battle-arena/message"
+)
+
+// Step advances state by one tick and returns the new state with what
+// happened during it. It doesn't change state or anything it points to and
+// has no other inputs, so a game is replayed by feeding the same inputs to
+// the same initial state.
+//
+// A tick respawns players whose delay is up, finishes reloads, applies the
+// inputs in player order, moves the bullets and then checks whether the
+// game is over. Flags, hills, the safe zone, pickups and power-ups only
+// exist on the server.
+//
+// Rooms don't run Step, but they play by the same rule functions it calls,
+// like PullTrigger, MoveBullet and ApplyDamage, so the two can't drift apart.
+func Step(state State, inputs []Input) (State, []Event) {
+	var next = state.clone()
+	if next.IsOver {
+		return next, nil
+	}
+	var events []Event
+	next.Tick++
+
+	for i := range next.Players {
+		var player = &next.Players[i]
+		if player.IsDead && !player.IsOut && player.RespawnAt <= next.Tick {
+			next.spawn(player)
+			events = append(events, Event{Type: EVENT_RESPAWN, Player: player.ID, Other: NO_ONE, Position: player.Position})
+		}
+		if player.ReloadAt != 0 && player.ReloadAt <= next.Tick {
+			var loadout = player.held()
+			loadout.Ammo, loadout.Reserve = WeaponByName(loadout.Weapon).Refill(loadout.Ammo, loadout.Reserve)
+			player.ReloadAt = 0
+			events = append(events, Event{Type: EVENT_RELOAD, Player: player.ID, Other: NO_ONE, Amount: loadout.Ammo})
+		}
+	}
+
+	var ordered = slices.Clone(inputs)
+	slices.SortStableFunc(ordered, func(a, b Input) int { return cmp.Compare(a.Player, b.Player) })
+	for _, input := range ordered {
+		events = next.apply(input, events)
+	}
+	events = next.moveBullets(events)
+	events = next.checkGameOver(events)
+	return next, events
+}
+
+// apply carries out one player's input. Inputs of dead or unknown players
+// are ignored.
+func (state *State) apply(input Input, events []Event) []Event {
+	var player = state.player(input.Player)
+	if player == nil || !player.isAlive() {
+		return events
+	}
+
+	if input.Weapon != "" && input.Weapon != player.held().Weapon {
+		for i, loadout := range player.Weapons {
+			if loadout.Weapon == input.Weapon {
+				player.Held = i
+				player.ReloadAt = 0
+				if NeedsReload(loadout.Ammo, loadout.Reserve) {
+					events = state.startReload(player, events)
+				}
+				break
+			}
+		}
+	}
+
+	var weapon = WeaponByName(player.held().Weapon)
+	if input.Reload && player.ReloadAt == 0 && weapon.CanReload(player.held().Ammo, player.held().Reserve) {
+		events = state.startReload(player, events)
+	}
+
+	if input.Move != nil && (input.Move.X != 0 || input.Move.Y != 0) {
+		var angle = NormalizeMovement(input.Move)
+		var step pb.Position
+		CalculateNewPosition(&pb.Position{}, &angle, PLAYER_SPEED, &step)
+		var bodies []*pb.Position
+		if state.Settings.PlayerCollision {
+			for _, other := range state.Players {
+				if other.ID != player.ID && other.isAlive() {
+					bodies = append(bodies, other.Position)
+				}
+			}
+		}
+		player.Position = SlideMove(state.Obstacles, PLAYER_SIZE, player.Position, &step, bodies)
+		player.Rotation = math.Atan2(input.Move.Y, input.Move.X)
+	}
+
+	if input.Shoot && player.ReloadAt == 0 && state.Tick >= player.NextShot {
+		var loadout = player.held()
+		var fired, reload bool
+		loadout.Ammo, fired, reload = PullTrigger(loadout.Ammo, loadout.Reserve)
+		if fired {
+			events = state.fire(player, weapon, events)
+		}
+		if reload {
+			events = state.startReload(player, events)
+		}
+	}
+	return events
+}
+
+// fire shoots the weapon the player holds, which has a round loaded.
+func (state *State) fire(player *Player, weapon *Weapon, events []Event) []Event {
+	player.NextShot = state.Tick + Ticks(weapon.FireRate)
+	for _, angle := range weapon.Angles(player.Rotation, state.RNG.Float64) {
+		state.Bullets = append(state.Bullets, Bullet{
+			ID:       state.NextBullet,
+			Owner:    player.ID,
+			Weapon:   weapon.Name,
+			Position: player.Position,
+			Rotation: angle,
+		})
+		state.NextBullet++
+	}
+	return append(events, Event{Type: EVENT_SHOOT, Player: player.ID, Other: NO_ONE, Amount: player.held().Ammo, Position: player.Position})
+}
+
+func (state *State) startReload(player *Player, events []Event) []Event {
+	player.ReloadAt = state.Tick + Ticks(WeaponByName(player.held().Weapon).ReloadTime)
+	return append(events, Event{Type: EVENT_RELOAD, Player: player.ID, Other: NO_ONE, Amount: player.held().Ammo})
+}
+
+// moveBullets flies every bullet one step like the server's bullet loop,
+// stopping it at the first obstacle or player it meets.
+func (state *State) moveBullets(events []Event) []Event {
+	var remaining = state.Bullets[:0]
+	for _, bullet := range state.Bullets {
+		var weapon = WeaponByName(bullet.Weapon)
+		var shooterTeam int32 = TEAM_NONE
+		if shooter := state.player(bullet.Owner); shooter != nil {
+			shooterTeam = shooter.Team
+		}
+		var spent bool
+		bullet.Position, bullet.Travelled, spent = MoveBullet(state.Obstacles, weapon, bullet.Position, bullet.Rotation, bullet.Travelled,
+			func(from, to *pb.Position, limit float64) (float64, bool) {
+				var target *Player
+				for i := range state.Players {
+					var player = &state.Players[i]
+					if player.ID == bullet.Owner || !player.isAlive() || IsFriendlyFire(state.Settings, shooterTeam, player.Team) {
+						continue
+					}
+					if t, ok := SegmentCircle(from, to, player.Position.X, player.Position.Y, PLAYER_SIZE); ok && t <= limit {
+						target, limit = player, t
+					}
+				}
+				if target == nil {
+					return 0, false
+				}
+				events = state.damage(target, weapon.Damage, bullet.Owner, events)
+				return limit, true
+			})
+		if !spent {
+			remaining = append(remaining, bullet)
+		}
+	}
+	state.Bullets = remaining
+	return events
+}
+
+// damage takes amount off a player's shield and health and handles their
+// death, attacker getting the kill when it counts.
+func (state *State) damage(player *Player, amount int32, attacker int32, events []Event) []Event {
+	player.Health, player.Shield = ApplyDamage(player.Health, player.Shield, amount)
+	if player.Health > 0 {
+		return append(events, Event{Type: EVENT_HIT, Player: player.ID, Other: attacker, Amount: player.Health})
+	}
+
+	player.IsDead = true
+	player.Deaths++
+	player.ReloadAt = 0
+	if HasRespawns(state.Settings) {
+		player.RespawnAt = state.Tick + Ticks(RespawnDelay(state.Settings))
+	} else {
+		player.IsOut = true
+	}
+	events = append(events, Event{Type: EVENT_DEATH, Player: player.ID, Other: attacker, Position: player.Position})
+	if killer := state.player(attacker); killer != nil && CountsAsKill(state.Settings, killer.Team, player.Team) {
+		killer.Kills++
+		events = append(events, Event{Type: EVENT_KILLS, Player: killer.ID, Other: NO_ONE, Amount: killer.Kills})
+	}
+	return events
+}
+
+// checkGameOver ends deathmatch at the kill limit, any respawning mode at
+// the time limit and last man standing once one side is left.
+func (state *State) checkGameOver(events []Event) []Event {
+	var kills = map[int32]int32{}
+	var standing = map[int32]bool{}
+	for _, player := range state.Players {
+		var side = state.side(player)
+		kills[side] += player.Kills
+		if !player.IsOut {
+			standing[side] = true
+		}
+	}
+
+	switch {
+	case !HasRespawns(state.Settings):
+		if len(standing) > 1 {
+			return events
+		}
+		for side := range standing {
+			state.Winner = side
+		}
+	case leader(kills) != NO_ONE && ReachedKillLimit(state.Settings, kills[leader(kills)]):
+		state.Winner = leader(kills)
+	case TimeLimit(state.Settings) > 0 && state.Tick >= Ticks(TimeLimit(state.Settings)):
+		state.Winner = leader(kills)
+	default:
+		return events
+	}
+	state.IsOver = true
+	state.Bullets = nil
+	return append(events, Event{Type: EVENT_GAME_OVER, Player: state.Winner, Other: NO_ONE})
+}
+
+// side is the player's team with teams on, or the player.
+func (state *State) side(player Player) int32 {
+	if state.Settings.Teams && IsValidTeam(player.Team) {
+		return player.Team
+	}
+	return player.ID
+}
+
+// leader returns the side with the most kills, or NO_ONE when it's tied.
+func leader(kills map[int32]int32) int32 {
+	var best, most, tied = int32(NO_ONE), int32(-1), false
+	for side, count := range kills {
+		switch {
+		case count > most:
+			best, most, tied = side, count, false
+		case count == most:
+			tied = true
+		}
+	}
+	if tied {
+		return NO_ONE
+	}
+	return best
+}
@@ -0,0 +1,195 @@
+package sim
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	pb "battle-arena/message"
+)
+
+func arena() *pb.GameMap {
+	return &pb.GameMap{
+		Width:  800,
+		Height: 400,
+		SpawnPoints: []*pb.Position{
+			{X: 100, Y: 100}, {X: 700, Y: 300}, {X: 100, Y: 300}, {X: 700, Y: 100},
+		},
+		Obstacles: []*pb.Obstacle{{X: 380, Y: 150, Width: 40, Height: 100}},
+	}
+}
+
+// summary writes out everything in a state and its events that a replay
+// must reproduce.
+func summary(state State, events []Event) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "tick %d over %v winner %d next %d\n", state.Tick, state.IsOver, state.Winner, state.NextBullet)
+	for _, player := range state.Players {
+		fmt.Fprintf(&out, "%d %v %v %v %v %v %v %v %v %v %v %v %v\n", player.ID, player.Position.X, player.Position.Y, player.Rotation,
+			player.Health, player.Kills, player.Deaths, player.IsDead, player.IsOut, player.Weapons, player.ReloadAt, player.NextShot, player.RespawnAt)
+	}
+	for _, bullet := range state.Bullets {
+		fmt.Fprintf(&out, "bullet %d %d %v %v %v\n", bullet.ID, bullet.Owner, bullet.Position.X, bullet.Position.Y, bullet.Travelled)
+	}
+	for _, event := range events {
+		fmt.Fprintf(&out, "event %+v\n", event)
+	}
+	return out.String()
+}
+
+// randomInputs plays every player like a twitchy client.
+func randomInputs(rng *rand.Rand, players int) []Input {
+	var inputs []Input
+	for ID := 0; ID < players; ID++ {
+		inputs = append(inputs, Input{
+			Player: int32(ID),
+			Move:   &pb.Position{X: float64(rng.Intn(3) - 1), Y: float64(rng.Intn(3) - 1)},
+			Shoot:  rng.Intn(4) == 0,
+			Reload: rng.Intn(50) == 0,
+		})
+	}
+	return inputs
+}
+
+func play(seed uint64, ticks int) string {
+	var settings = &pb.GameSettings{Mode: MODE_DEATHMATCH, RespawnDelay: 500, PlayerCollision: true}
+	var state = NewState(arena(), settings, seed, []int32{TEAM_NONE, TEAM_NONE, TEAM_NONE, TEAM_NONE})
+	var rng = rand.New(rand.NewSource(int64(seed)))
+	var out strings.Builder
+	for i := 0; i < ticks; i++ {
+		var events []Event
+		state, events = Step(state, randomInputs(rng, len(state.Players)))
+		out.WriteString(summary(state, events))
+	}
+	return out.String()
+}
+
+func TestStepIsDeterministic(t *testing.T) {
+	var first, second = play(7, 2000), play(7, 2000)
+	if first != second {
+		t.Fatal("the same seed and inputs played out differently")
+	}
+	if !strings.Contains(first, "Type:"+EVENT_HIT) {
+		t.Fatal("expected somebody to get hit in 2000 ticks of random play")
+	}
+	if play(8, 2000) == first {
+		t.Fatal("different seeds played out the same")
+	}
+}
+
+func TestStepLeavesStateAlone(t *testing.T) {
+	var state = NewState(arena(), &pb.GameSettings{Mode: MODE_DEATHMATCH}, 1, []int32{TEAM_NONE, TEAM_NONE})
+	var rng = rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		var before = summary(state, nil)
+		next, _ := Step(state, randomInputs(rng, len(state.Players)))
+		if after := summary(state, nil); after != before {
+			t.Fatalf("Step changed its input state at tick %d:\n%s\nbecame\n%s", state.Tick, before, after)
+		}
+		state = next
+	}
+}
+
+// duel puts player 0 facing player 1 across open ground and has them shoot
+// until the game ends or ticks run out.
+func duel(settings *pb.GameSettings, ticks int) (State, []Event) {
+	var state = NewState(&pb.GameMap{Width: 800, Height: 400, SpawnPoints: []*pb.Position{{X: 100, Y: 200}, {X: 300, Y: 200}}},
+		settings, 1, []int32{TEAM_NONE, TEAM_NONE})
+	state.Players[0].Position = &pb.Position{X: 100, Y: 200}
+	state.Players[1].Position = &pb.Position{X: 300, Y: 200}
+	var all []Event
+	for i := 0; i < ticks && !state.IsOver; i++ {
+		var events []Event
+		state, events = Step(state, []Input{{Player: 0, Shoot: true}})
+		all = append(all, events...)
+	}
+	return state, all
+}
+
+func TestStepDeathmatchKill(t *testing.T) {
+	var state, events = duel(&pb.GameSettings{Mode: MODE_DEATHMATCH, KillLimit: 1, RespawnDelay: 1000}, 2000)
+	if !state.IsOver || state.Winner != 0 {
+		t.Fatalf("game over %v, winner %d, want player 0 to win", state.IsOver, state.Winner)
+	}
+	if state.Players[0].Kills != 1 || state.Players[1].Deaths != 1 || !state.Players[1].IsDead || state.Players[1].IsOut {
+		t.Fatalf("players after the kill: %+v", state.Players)
+	}
+	var last = events[len(events)-1]
+	if last.Type != EVENT_GAME_OVER || last.Player != 0 {
+		t.Fatalf("last event %+v, want game over won by 0", last)
+	}
+}
+
+func TestStepRespawns(t *testing.T) {
+	var state, events = duel(&pb.GameSettings{Mode: MODE_DEATHMATCH, RespawnDelay: 100}, 600)
+	var deaths, respawns int
+	for _, event := range events {
+		switch event.Type {
+		case EVENT_DEATH:
+			deaths++
+		case EVENT_RESPAWN:
+			respawns++
+		}
+	}
+	if deaths == 0 || respawns == 0 || state.IsOver {
+		t.Fatalf("%d deaths, %d respawns, over %v; want the duel to keep going", deaths, respawns, state.IsOver)
+	}
+}
+
+func TestStepLastManStanding(t *testing.T) {
+	var state, _ = duel(&pb.GameSettings{Mode: MODE_LAST_MAN_STANDING}, 2000)
+	if !state.IsOver || state.Winner != 0 || !state.Players[1].IsOut {
+		t.Fatalf("game over %v, winner %d, player 1 out %v", state.IsOver, state.Winner, state.Players[1].IsOut)
+	}
+	if next, events := Step(state, []Input{{Player: 0, Shoot: true}}); len(events) != 0 || next.Tick != state.Tick {
+		t.Fatal("Step kept going after the game ended")
+	}
+}
+
+func TestStepFriendlyFire(t *testing.T) {
+	var settings = &pb.GameSettings{Mode: MODE_DEATHMATCH, Teams: true}
+	var state = NewState(arena(), settings, 1, []int32{TEAM_RED, TEAM_RED})
+	state.Players[0].Position = &pb.Position{X: 100, Y: 200}
+	state.Players[1].Position = &pb.Position{X: 200, Y: 200}
+	for i := 0; i < 300; i++ {
+		state, _ = Step(state, []Input{{Player: 0, Shoot: true}})
+	}
+	if state.Players[1].Health != PLAYER_MAX_HEALTH {
+		t.Fatalf("teammate health %d with friendly fire off", state.Players[1].Health)
+	}
+}
+
+func TestPullTrigger(t *testing.T) {
+	for _, test := range []struct {
+		ammo, reserve, left int32
+		fired, reload       bool
+	}{
+		{ammo: 3, reserve: 6, left: 2, fired: true},
+		{ammo: 1, reserve: 6, left: 0, fired: true, reload: true},
+		{ammo: 1, reserve: 0, left: 0, fired: true},
+		{ammo: 0, reserve: 6, left: 0, reload: true},
+		{ammo: 0, reserve: 0, left: 0},
+	} {
+		if left, fired, reload := PullTrigger(test.ammo, test.reserve); left != test.left || fired != test.fired || reload != test.reload {
+			t.Errorf("PullTrigger(%d, %d) = %d, %v, %v, want %d, %v, %v", test.ammo, test.reserve, left, fired, reload, test.left, test.fired, test.reload)
+		}
+	}
+	if ammo, reserve := WEAPONS[WEAPON_PISTOL].Refill(4, 5); ammo != 9 || reserve != 0 {
+		t.Errorf("Refill(4, 5) = %d, %d, want 9, 0", ammo, reserve)
+	}
+}
+
+func BenchmarkStep(b *testing.B) {
+	var settings = &pb.GameSettings{Mode: MODE_DEATHMATCH, RespawnDelay: 500}
+	var state = NewState(arena(), settings, 1, []int32{TEAM_NONE, TEAM_NONE, TEAM_NONE, TEAM_NONE, TEAM_NONE, TEAM_NONE})
+	var rng = rand.New(rand.NewSource(1))
+	var inputs = make([][]Input, 1024)
+	for i := range inputs {
+		inputs[i] = randomInputs(rng, len(state.Players))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		state, _ = Step(state, inputs[i%len(inputs)])
+	}
+}
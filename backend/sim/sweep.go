@@ -0,0 +1,128 @@
+package sim
+
+import (
+	"math"
+
+	pb "battle-arena/message"
+)
+
+// Swept tests return how far along the segment from -> to the first contact
+// is, from 0 at from to 1 at to. Only touching an edge isn't a hit, the same
+// as for IsBlocked.
+
+// SegmentAABB returns where the segment first enters the open box, or false
+// if it never does. A segment starting inside hits at 0.
+func SegmentAABB(from, to *pb.Position, minX, minY, maxX, maxY float64) (float64, bool) {
+	var enter, exit = 0.0, 1.0
+	for _, axis := range [2][4]float64{{from.X, to.X - from.X, minX, maxX}, {from.Y, to.Y - from.Y, minY, maxY}} {
+		var start, delta, low, high = axis[0], axis[1], axis[2], axis[3]
+		if delta == 0 {
+			if start <= low || start >= high {
+				return 0, false
+			}
+			continue
+		}
+		var near, far = (low - start) / delta, (high - start) / delta
+		if near > far {
+			near, far = far, near
+		}
+		enter, exit = max(enter, near), min(exit, far)
+		if enter >= exit {
+			return 0, false
+		}
+	}
+	return enter, true
+}
+
+// SegmentCircle returns where the segment first enters the open circle, or
+// false if it never does. A segment starting inside hits at 0.
+func SegmentCircle(from, to *pb.Position, x, y, radius float64) (float64, bool) {
+	var dx, dy = to.X - from.X, to.Y - from.Y
+	var fx, fy = from.X - x, from.Y - y
+	var c = fx*fx + fy*fy - radius*radius
+	if c < 0 {
+		return 0, true
+	}
+	var a = dx*dx + dy*dy
+	if a == 0 {
+		return 0, false
+	}
+	var b = 2 * (fx*dx + fy*dy)
+	var discriminant = b*b - 4*a*c
+	if discriminant <= 0 {
+		return 0, false
+	}
+	var t = (-b - math.Sqrt(discriminant)) / (2 * a)
+	if t < 0 || t >= 1 {
+		return 0, false
+	}
+	return t, true
+}
+
+// SegmentRoundedRect returns where the segment first enters the box grown
+// by radius with rounded corners, which is where a circle of that radius
+// moving along the segment first touches the box.
+func SegmentRoundedRect(from, to *pb.Position, minX, minY, maxX, maxY, radius float64) (float64, bool) {
+	var first, hit = SegmentAABB(from, to, minX-radius, minY, maxX+radius, maxY)
+	var consider = func(t float64, ok bool) {
+		if ok && (!hit || t < first) {
+			first, hit = t, true
+		}
+	}
+	consider(SegmentAABB(from, to, minX, minY-radius, maxX, maxY+radius))
+	if radius > 0 {
+		for _, corner := range [4][2]float64{{minX, minY}, {maxX, minY}, {minX, maxY}, {maxX, maxY}} {
+			consider(SegmentCircle(from, to, corner[0], corner[1], radius))
+		}
+	}
+	return first, hit
+}
+
+// SegmentBounds returns where a circle of radius size moving along the
+// segment first leaves the map, or false if it stays inside.
+func SegmentBounds(gameMap *pb.GameMap, size float64, from, to *pb.Position) (float64, bool) {
+	if IsOutOfBounds(gameMap, size, from) {
+		return 0, true
+	}
+	var exit = math.Inf(1)
+	for _, axis := range [2][3]float64{{from.X, to.X, float64(gameMap.Width)}, {from.Y, to.Y, float64(gameMap.Height)}} {
+		var start, end, limit = axis[0], axis[1], axis[2]
+		if end-size < 0 {
+			exit = min(exit, (start-size)/(start-end))
+		} else if end+size > limit {
+			exit = min(exit, (limit-size-start)/(end-start))
+		}
+	}
+	return exit, !math.IsInf(exit, 1)
+}
+
+// Sweep returns where a circle of radius size moving from -> to first
+// touches an obstacle or leaves the map, or false if the way is clear. It's
+// the continuous version of IsBlocked.
+func (index *ObstacleIndex) Sweep(size float64, from, to *pb.Position) (float64, bool) {
+	var first, hit = SegmentBounds(index.gameMap, size, from, to)
+	var minX, minY = min(from.X, to.X) - size, min(from.Y, to.Y) - size
+	var maxX, maxY = max(from.X, to.X) + size, max(from.Y, to.Y) + size
+
+	index.rects.Query(minX, minY, maxX, maxY, func(obstacle *pb.Obstacle) bool {
+		t, ok := SegmentRoundedRect(from, to, float64(obstacle.X), float64(obstacle.Y),
+			float64(obstacle.X+obstacle.Width), float64(obstacle.Y+obstacle.Height), size)
+		if ok && (!hit || t < first) {
+			first, hit = t, true
+		}
+		return false
+	})
+	index.circles.Query(minX, minY, maxX, maxY, func(circle *pb.CircleObstacle) bool {
+		t, ok := SegmentCircle(from, to, float64(circle.X), float64(circle.Y), float64(circle.Radius)+size)
+		if ok && (!hit || t < first) {
+			first, hit = t, true
+		}
+		return false
+	})
+	return first, hit
+}
+
+// PointAlong returns the point t of the way from from to to.
+func PointAlong(from, to *pb.Position, t float64) *pb.Position {
+	return &pb.Position{X: from.X + (to.X-from.X)*t, Y: from.Y + (to.Y-from.Y)*t}
+}
@@ -0,0 +1,117 @@
+package sim
+
+import (
+	"math"
+	"testing"
+
+	pb "battle-arena/message"
+)
+
+const SWEEP_EPSILON = 1e-9
+
+func checkSweep(t *testing.T, gotT float64, gotHit bool, wantT float64, wantHit bool) {
+	t.Helper()
+	if gotHit != wantHit {
+		t.Fatalf("hit = %v, want %v", gotHit, wantHit)
+	}
+	if wantHit && math.Abs(gotT-wantT) > SWEEP_EPSILON {
+		t.Fatalf("t = %v, want %v", gotT, wantT)
+	}
+}
+
+func TestSegmentAABB(t *testing.T) {
+	// the box spans (10, 10) to (20, 20)
+	var tests = []struct {
+		name     string
+		from, to *pb.Position
+		t        float64
+		hit      bool
+	}{
+		{"head on from the left", &pb.Position{X: 0, Y: 15}, &pb.Position{X: 30, Y: 15}, 1.0 / 3, true},
+		{"head on from the right", &pb.Position{X: 30, Y: 15}, &pb.Position{X: 0, Y: 15}, 1.0 / 3, true},
+		{"from above", &pb.Position{X: 15, Y: 0}, &pb.Position{X: 15, Y: 20}, 0.5, true},
+		{"diagonal through a corner", &pb.Position{X: 0, Y: 0}, &pb.Position{X: 20, Y: 20}, 0.5, true},
+		{"starts inside", &pb.Position{X: 15, Y: 15}, &pb.Position{X: 40, Y: 15}, 0, true},
+		{"ends inside", &pb.Position{X: 0, Y: 15}, &pb.Position{X: 15, Y: 15}, 2.0 / 3, true},
+		{"zero length inside", &pb.Position{X: 15, Y: 15}, &pb.Position{X: 15, Y: 15}, 0, true},
+		{"thin wall passed in one step", &pb.Position{X: 0, Y: 15}, &pb.Position{X: 1000, Y: 15}, 0.01, true},
+		{"stops short", &pb.Position{X: 0, Y: 15}, &pb.Position{X: 9, Y: 15}, 0, false},
+		{"ends on the edge", &pb.Position{X: 0, Y: 15}, &pb.Position{X: 10, Y: 15}, 0, false},
+		{"slides along an edge", &pb.Position{X: 0, Y: 10}, &pb.Position{X: 30, Y: 10}, 0, false},
+		{"touches a corner", &pb.Position{X: 0, Y: 20}, &pb.Position{X: 20, Y: 0}, 0, false},
+		{"parallel and outside", &pb.Position{X: 0, Y: 25}, &pb.Position{X: 30, Y: 25}, 0, false},
+		{"moving away", &pb.Position{X: 25, Y: 15}, &pb.Position{X: 40, Y: 15}, 0, false},
+		{"zero length outside", &pb.Position{X: 5, Y: 5}, &pb.Position{X: 5, Y: 5}, 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotT, gotHit := SegmentAABB(test.from, test.to, 10, 10, 20, 20)
+			checkSweep(t, gotT, gotHit, test.t, test.hit)
+		})
+	}
+}
+
+func TestSegmentCircle(t *testing.T) {
+	// the circle has radius 10 around (50, 50)
+	var tests = []struct {
+		name     string
+		from, to *pb.Position
+		t        float64
+		hit      bool
+	}{
+		{"head on", &pb.Position{X: 0, Y: 50}, &pb.Position{X: 100, Y: 50}, 0.4, true},
+		{"from below", &pb.Position{X: 50, Y: 100}, &pb.Position{X: 50, Y: 0}, 0.4, true},
+		{"off center", &pb.Position{X: 0, Y: 56}, &pb.Position{X: 100, Y: 56}, 0.42, true},
+		{"starts inside", &pb.Position{X: 50, Y: 50}, &pb.Position{X: 100, Y: 50}, 0, true},
+		{"ends inside", &pb.Position{X: 30, Y: 50}, &pb.Position{X: 50, Y: 50}, 0.5, true},
+		{"passes through in one step", &pb.Position{X: 0, Y: 50}, &pb.Position{X: 1000, Y: 50}, 0.04, true},
+		{"tangent", &pb.Position{X: 0, Y: 60}, &pb.Position{X: 100, Y: 60}, 0, false},
+		{"stops short", &pb.Position{X: 0, Y: 50}, &pb.Position{X: 39, Y: 50}, 0, false},
+		{"ends on the edge", &pb.Position{X: 0, Y: 50}, &pb.Position{X: 40, Y: 50}, 0, false},
+		{"moving away", &pb.Position{X: 70, Y: 50}, &pb.Position{X: 100, Y: 50}, 0, false},
+		{"misses", &pb.Position{X: 0, Y: 0}, &pb.Position{X: 100, Y: 10}, 0, false},
+		{"zero length outside", &pb.Position{X: 0, Y: 0}, &pb.Position{X: 0, Y: 0}, 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotT, gotHit := SegmentCircle(test.from, test.to, 50, 50, 10)
+			checkSweep(t, gotT, gotHit, test.t, test.hit)
+		})
+	}
+}
+
+func TestObstacleSweep(t *testing.T) {
+	var gameMap = &pb.GameMap{
+		Width:           200,
+		Height:          100,
+		Obstacles:       []*pb.Obstacle{{X: 100, Y: 0, Width: 2, Height: 40}, {X: 150, Y: 20, Width: 10, Height: 40}},
+		CircleObstacles: []*pb.CircleObstacle{{X: 60, Y: 80, Radius: 10}},
+	}
+	var index = NewObstacleIndex(gameMap)
+
+	var tests = []struct {
+		name     string
+		size     float64
+		from, to *pb.Position
+		t        float64
+		hit      bool
+	}{
+		{"clear", 0, &pb.Position{X: 10, Y: 50}, &pb.Position{X: 140, Y: 50}, 0, false},
+		{"thin wall", 0, &pb.Position{X: 10, Y: 20}, &pb.Position{X: 190, Y: 20}, 0.5, true},
+		{"earliest of two", 0, &pb.Position{X: 190, Y: 30}, &pb.Position{X: 10, Y: 30}, 30.0 / 180, true},
+		{"circle", 0, &pb.Position{X: 10, Y: 80}, &pb.Position{X: 110, Y: 80}, 0.4, true},
+		{"size grows obstacles", 4, &pb.Position{X: 10, Y: 50}, &pb.Position{X: 140, Y: 50}, 0, false},
+		{"size reaches the wall", 4, &pb.Position{X: 10, Y: 30}, &pb.Position{X: 140, Y: 30}, 86.0 / 130, true},
+		{"size reaches a corner", 4, &pb.Position{X: 10, Y: 43}, &pb.Position{X: 140, Y: 43}, (90 - math.Sqrt(7)) / 130, true},
+		{"size misses a rounded corner", 4, &pb.Position{X: 98.6, Y: 50}, &pb.Position{X: 118.6, Y: 30}, 0, false},
+		{"leaves the map", 0, &pb.Position{X: 10, Y: 50}, &pb.Position{X: 10, Y: 150}, 0.5, true},
+		{"leaves the map with size", 4, &pb.Position{X: 190, Y: 70}, &pb.Position{X: 210, Y: 70}, 0.3, true},
+		{"starts outside", 0, &pb.Position{X: -10, Y: 50}, &pb.Position{X: 10, Y: 50}, 0, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotT, gotHit := index.Sweep(test.size, test.from, test.to)
+			checkSweep(t, gotT, gotHit, test.t, test.hit)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package sim
+
+import (
+	"time"
+
+	pb "battle-arena/message"
+)
+
+// Weapons
+const (
+	WEAPON_PISTOL  = "pistol"
+	WEAPON_SHOTGUN = "shotgun"
+	WEAPON_SNIPER  = "sniper"
+	WEAPON_SMG     = "smg"
+	DEFAULT_WEAPON = WEAPON_PISTOL
+	// spare magazines that come with a new weapon or an ammo pickup
+	RESERVE_MAGAZINES = 2
+)
+
+type Weapon struct {
+	Name        string
+	FireRate    time.Duration // minimum time between shots
+	Speed       float64       // per bullet tick
+	Damage      int32
+	Spread      float64 // radians across the whole cone
+	Projectiles int
+	Range       float64 // distance a bullet travels before it expires
+	Size        float64
+	Magazine    int32
+	MaxReserve  int32 // spare rounds a player can carry
+	ReloadTime  time.Duration
+}
+
+var WEAPONS = map[string]*Weapon{
+	WEAPON_PISTOL: {
+		Name:        WEAPON_PISTOL,
+		FireRate:    300 * time.Millisecond,
+		Speed:       BULLET_SPEED,
+		Damage:      BULLET_DAMAGE,
+		Spread:      0.04,
+		Projectiles: 1,
+		Range:       700,
+		Size:        BULLET_SIZE,
+		Magazine:    12,
+		MaxReserve:  60,
+		ReloadTime:  1200 * time.Millisecond,
+	},
+	WEAPON_SHOTGUN: {
+		Name:        WEAPON_SHOTGUN,
+		FireRate:    900 * time.Millisecond,
+		Speed:       6,
+		Damage:      8,
+		Spread:      0.5,
+		Projectiles: 6,
+		Range:       300,
+		Size:        3,
+		Magazine:    6,
+		MaxReserve:  24,
+		ReloadTime:  2 * time.Second,
+	},
+	WEAPON_SNIPER: {
+		Name:        WEAPON_SNIPER,
+		FireRate:    1500 * time.Millisecond,
+		Speed:       14,
+		Damage:      45,
+		Spread:      0,
+		Projectiles: 1,
+		Range:       1500,
+		Size:        3,
+		Magazine:    4,
+		MaxReserve:  12,
+		ReloadTime:  2500 * time.Millisecond,
+	},
+	WEAPON_SMG: {
+		Name:        WEAPON_SMG,
+		FireRate:    100 * time.Millisecond,
+		Speed:       8,
+		Damage:      5,
+		Spread:      0.15,
+		Projectiles: 1,
+		Range:       500,
+		Size:        3,
+		Magazine:    30,
+		MaxReserve:  120,
+		ReloadTime:  1800 * time.Millisecond,
+	},
+}
+
+// WeaponByName falls back to the default weapon for unknown names, so
+// bullets and players without a weapon behave like they always did.
+func WeaponByName(name string) *Weapon {
+	if weapon, ok := WEAPONS[name]; ok {
+		return weapon
+	}
+	return WEAPONS[DEFAULT_WEAPON]
+}
+
+// FullLoad is the ammo a new weapon comes with: a full magazine and
+// RESERVE_MAGAZINES spare ones.
+func (weapon *Weapon) FullLoad() (int32, int32) {
+	return weapon.Magazine, weapon.Magazine * RESERVE_MAGAZINES
+}
+
+// CanReload reports whether a reload would load any of the spare rounds.
+func (weapon *Weapon) CanReload(ammo, reserve int32) bool {
+	return reserve > 0 && ammo < weapon.Magazine
+}
+
+// Refill moves as many spare rounds into the magazine as fit and returns
+// the new magazine and reserve.
+func (weapon *Weapon) Refill(ammo, reserve int32) (int32, int32) {
+	var refill = min(weapon.Magazine-ammo, reserve)
+	return ammo + refill, reserve - refill
+}
+
+// NeedsReload reports whether a magazine is empty with spare rounds to load.
+func NeedsReload(ammo, reserve int32) bool {
+	return ammo == 0 && reserve > 0
+}
+
+// PullTrigger fires a round from a magazine of ammo rounds when there's one
+// loaded. It returns the rounds left, whether it fired and whether the
+// magazine now needs reloading, which also loads ammo picked up since it ran
+// dry.
+func PullTrigger(ammo, reserve int32) (int32, bool, bool) {
+	if ammo <= 0 {
+		return ammo, false, reserve > 0
+	}
+	ammo--
+	return ammo, true, NeedsReload(ammo, reserve)
+}
+
+// Angles returns the directions the weapon's projectiles leave in when fired
+// at angle rotation. A single projectile deviates randomly within the
+// spread, random giving numbers in [0, 1); several are fanned out evenly
+// across it.
+func (weapon *Weapon) Angles(rotation float64, random func() float64) []float64 {
+	var angles []float64
+	for i := 0; i < weapon.Projectiles; i++ {
+		var angle = rotation + (random()-0.5)*weapon.Spread
+		if weapon.Projectiles > 1 {
+			angle = rotation - weapon.Spread/2 + weapon.Spread*float64(i)/float64(weapon.Projectiles-1)
+		}
+		angles = append(angles, angle)
+	}
+	return angles
+}
+
+// MoveBullet flies a bullet of weapon one tick from position at angle
+// rotation, travelled into its range. hit looks for a player on the step
+// from -> to before limit, the fraction of the way an obstacle lets it get,
+// and returns how far along it is. MoveBullet returns where the bullet ends
+// up, how far it has travelled and whether it's spent.
+func MoveBullet(obstacles *ObstacleIndex, weapon *Weapon, position *pb.Position, rotation, travelled float64,
+	hit func(from, to *pb.Position, limit float64) (float64, bool)) (*pb.Position, float64, bool) {
+	var step = min(weapon.Speed, weapon.Range-travelled)
+	var to pb.Position
+	CalculateNewPosition(position, &rotation, step, &to)
+	travelled += step
+
+	var limit, spent = obstacles.Sweep(weapon.Size, position, &to)
+	if !spent {
+		limit = 1
+	}
+	if t, ok := hit(position, &to, limit); ok {
+		limit, spent = t, true
+	}
+	return PointAlong(position, &to, limit), travelled, spent || travelled >= weapon.Range
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"sync"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+// playerIndex files the players of a room by position for hit queries. Its
+// lock is taken after room.mu and player.mu. Bullets have no index: they
+// never hit each other, so nothing asks which bullets are near.
+type playerIndex struct {
+	hash      *sim.SpatialHash[int32]
+	positions map[int32]*pb.Position
+	mu        sync.Mutex
+}
+
+func newPlayerIndex(gameMap *pb.GameMap) *playerIndex {
+	return &playerIndex{
+		hash:      sim.NewSpatialHash[int32](gameMap.Width, gameMap.Height),
+		positions: map[int32]*pb.Position{},
+	}
+}
+
+// update moves player ID to position, adding them if they aren't indexed.
+func (index *playerIndex) update(ID int32, position *pb.Position) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	if previous, ok := index.positions[ID]; ok {
+		index.hash.Remove(ID, previous.X, previous.Y, previous.X, previous.Y)
+	}
+	index.positions[ID] = position
+	index.hash.Insert(ID, position.X, position.Y, position.X, position.Y)
+}
+
+func (index *playerIndex) remove(ID int32) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	if previous, ok := index.positions[ID]; ok {
+		index.hash.Remove(ID, previous.X, previous.Y, previous.X, previous.Y)
+		delete(index.positions, ID)
+	}
+}
+
+// near returns the players that may be within radius of the segment from
+// -> to. Callers still check the exact distance.
+func (index *playerIndex) near(from, to *pb.Position, radius float64) []int32 {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	var IDs []int32
+	var minX, minY = min(from.X, to.X) - radius, min(from.Y, to.Y) - radius
+	var maxX, maxY = max(from.X, to.X) + radius, max(from.Y, to.Y) + radius
+	index.hash.Query(minX, minY, maxX, maxY, func(ID int32) bool {
+		IDs = append(IDs, ID)
+		return false
+	})
+	return IDs
+}
+
+// bodiesNear returns where the living players a move of player ID from
+// position could bump into stand, when the room has player collision on.
+// The caller holds room.mu.
+func (room *Room) bodiesNear(ID int32, position *pb.Position) []*pb.Position {
+	if !room.settings.PlayerCollision {
+		return nil
+	}
+	var bodies []*pb.Position
+	for _, other := range room.index.near(position, position, 2*sim.PLAYER_SIZE+sim.PLAYER_SPEED*SPEED_BOOST_FACTOR) {
+		var player = room.player[other]
+		if player == nil || other == ID {
+			continue
+		}
+		player.mu.RLock()
+		if !player.IsDead {
+			bodies = append(bodies, player.Position)
+		}
+		player.mu.RUnlock()
+	}
+	return bodies
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+func TestPlayerIndex(t *testing.T) {
+	var index = newPlayerIndex(&pb.GameMap{Width: 1600, Height: 1600})
+	index.update(0, &pb.Position{X: 100, Y: 100})
+	index.update(1, &pb.Position{X: 1500, Y: 1500})
+	index.update(2, &pb.Position{X: 120, Y: 100})
+
+	var contains = func(IDs []int32, ID int32) bool {
+		for _, other := range IDs {
+			if other == ID {
+				return true
+			}
+		}
+		return false
+	}
+	var near = index.near(&pb.Position{X: 110, Y: 100}, &pb.Position{X: 110, Y: 100}, sim.PLAYER_SIZE)
+	if !contains(near, 0) || !contains(near, 2) || contains(near, 1) {
+		t.Fatalf("near (110, 100) = %v, want 0 and 2", near)
+	}
+
+	index.update(2, &pb.Position{X: 1490, Y: 1500})
+	index.remove(0)
+	near = index.near(&pb.Position{X: 110, Y: 100}, &pb.Position{X: 110, Y: 100}, sim.PLAYER_SIZE)
+	if contains(near, 0) || contains(near, 2) {
+		t.Fatalf("near (110, 100) = %v after moving and removing", near)
+	}
+	if near = index.near(&pb.Position{X: 1500, Y: 1500}, &pb.Position{X: 1500, Y: 1500}, sim.PLAYER_SIZE); !contains(near, 1) || !contains(near, 2) {
+		t.Fatalf("near (1500, 1500) = %v, want 1 and 2", near)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"math/rand"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+// spawnPosition picks a spawn point for player ID away from everyone else in
+// the room. The caller must hold room.mu.
+func (room *Room) spawnPosition(ID int32) *pb.Position {
+	var others []*pb.Position
+	for _, player := range room.player {
+		if player == nil || player.Id == ID {
+			continue
+		}
+		player.mu.RLock()
+		if player.Position != nil {
+			others = append(others, player.Position)
+		}
+		player.mu.RUnlock()
+	}
+	return sim.PickSpawnPoint(room.obstacles, others, rand.Float64)
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"google.golang.org/protobuf/proto"
+
+	pb "battle-arena/message"
+)
+
+// DEFAULT_MAX_SPECTATORS caps spectators from outside; eliminated players always stay.
+const DEFAULT_MAX_SPECTATORS = 8
+
+func maxSpectators() int {
+	if limit, err := strconv.Atoi(os.Getenv("MAX_SPECTATORS")); err == nil && limit >= 0 {
+		return limit
+	}
+	return DEFAULT_MAX_SPECTATORS
+}
+
+// Spectator watches a room without a slot and only chooses whose camera to follow.
+type Spectator struct {
+	*outbox
+	ID   int32
+	Conn *net.Conn
+	// the player the camera follows, nil for a free camera
+	following  *int32
+	eliminated bool
+	mu         sync.Mutex
+}
+
+// addSpectator lets conn watch the room. The caller holds room.mu for writing.
+func (room *Room) addSpectator(conn *net.Conn, out *outbox, follow *int32, eliminated bool) *Spectator {
+	var outside int
+	for _, other := range room.spectators {
+		if !other.eliminated {
+			outside++
+		}
+	}
+	if !eliminated && outside >= maxSpectators() {
+		return nil
+	}
+	if follow != nil && (*follow < 0 || int(*follow) >= len(room.player) || room.player[*follow] == nil) {
+		follow = nil
+	}
+	if room.spectators == nil {
+		room.spectators = map[int32]*Spectator{}
+	}
+	var spectator = &Spectator{
+		outbox:     out,
+		ID:         room.nextSpectator,
+		Conn:       conn,
+		following:  follow,
+		eliminated: eliminated,
+	}
+	room.nextSpectator++
+	room.spectators[spectator.ID] = spectator
+	metrics.connectedSpectators.Add(1)
+	return spectator
+}
+
+// removeSpectator forgets a spectator and closes its connection.
+func (room *Room) removeSpectator(ID int32) {
+	room.mu.Lock()
+	var spectator = room.spectators[ID]
+	delete(room.spectators, ID)
+	room.mu.Unlock()
+	if spectator == nil {
+		return
+	}
+	metrics.connectedSpectators.Add(-1)
+	spectator.close()
+	room.logger().Info("spectator left", "spectator", ID)
+}
+
+// removeSpectators closes every spectator's connection.
+func (room *Room) removeSpectators() {
+	room.mu.RLock()
+	var IDs []int32
+	for ID := range room.spectators {
+		IDs = append(IDs, ID)
+	}
+	room.mu.RUnlock()
+	for _, ID := range IDs {
+		room.removeSpectator(ID)
+	}
+}
+
+// sendSpectators queues an already marshalled message for every spectator.
+func (room *Room) sendSpectators(event string, data []byte) {
+	for _, spectator := range room.spectators {
+		spectator.send(event, data)
+	}
+}
+
+// worldPayload describes the room for a client that starts watching it.
+func (room *Room) worldPayload() *pb.Payload {
+	var payload = &pb.Payload{
+		Players:  []*pb.Player{},
+		Map:      room.gameMap,
+		Settings: room.settings,
+	}
+	for _, player := range room.player {
+		if player != nil {
+			player.mu.RLock()
+			payload.Players = append(payload.Players, player.toProto())
+			player.mu.RUnlock()
+		}
+	}
+	if !room.IsGameStarted {
+		return payload
+	}
+
+	payload.Pickups = room.pickups.toProto()
+	if room.isCaptureTheFlag() {
+		payload.Flags = room.ctf.toProto()
+	}
+	if room.isKingOfTheHill() {
+		room.koth.mu.Lock()
+		payload.Zone = &pb.ControlZone{Position: room.koth.position, Radius: room.koth.radius}
+		room.koth.mu.Unlock()
+	}
+	if room.safeZone != nil {
+		room.safeZone.mu.Lock()
+		payload.SafeZone = room.safeZone.toProto(time.Now())
+		room.safeZone.mu.Unlock()
+	}
+	return payload
+}
+
+// welcomeSpectator queues the SPECTATE that sets up a new spectator's view.
+func (room *Room) welcomeSpectator(spectator *Spectator) {
+	var msg = pb.Message{Event: SPECTATE, Payload: room.worldPayload()}
+	spectator.mu.Lock()
+	msg.Payload.Target = spectator.following
+	spectator.mu.Unlock()
+	if data, err := proto.Marshal(&msg); err == nil {
+		spectator.send(SPECTATE, data)
+	}
+}
+
+// follow points a spectator's camera at player target, or frees it when nil.
+func (room *Room) follow(spectator *Spectator, target *int32) {
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+	if target != nil && (*target < 0 || int(*target) >= len(room.player) || room.player[*target] == nil) {
+		return
+	}
+
+	spectator.mu.Lock()
+	spectator.following = target
+	spectator.mu.Unlock()
+	if data, err := proto.Marshal(&pb.Message{Event: FOLLOW, Payload: &pb.Payload{Target: target}}); err == nil {
+		spectator.send(FOLLOW, data)
+	}
+}
+
+// unfollow moves the cameras following player ID, who's leaving, to someone else.
+func (room *Room) unfollow(ID int32) {
+	var next *int32
+	for i := 1; i < len(room.player); i++ {
+		var other = (int(ID) + i) % len(room.player)
+		if room.player[other] != nil {
+			var target = int32(other)
+			next = &target
+			break
+		}
+	}
+
+	for _, spectator := range room.spectators {
+		spectator.mu.Lock()
+		var following = spectator.following != nil && *spectator.following == ID
+		if following {
+			spectator.following = next
+		}
+		spectator.mu.Unlock()
+		if !following {
+			continue
+		}
+		if data, err := proto.Marshal(&pb.Message{Event: FOLLOW, Payload: &pb.Payload{Target: next}}); err == nil {
+			spectator.send(FOLLOW, data)
+		}
+	}
+}
+
+// handleSpectatorMessage acts on a spectator's FOLLOW and ignores anything else.
+func (room *Room) handleSpectatorMessage(spectator *Spectator, msg *pb.Message) {
+	if msg.Event != FOLLOW {
+		return
+	}
+	var target *int32
+	if msg.Payload != nil {
+		target = msg.Payload.Target
+	}
+	room.follow(spectator, target)
+}
+
+// spectateRoom handles /play?roomId=N&spectate, with follow=ID to pick a camera.
+func spectateRoom(w http.ResponseWriter, r *http.Request) {
+	_, roomID, _ := parseParams(r)
+	value, ok := rooms.Load(roomID)
+	if !ok {
+		http.Error(w, "Invalid Room Id", http.StatusBadRequest)
+		return
+	}
+	var room = value.(*Room)
+	var follow *int32
+	if ID, err := strconv.Atoi(r.URL.Query().Get("follow")); err == nil {
+		var target = int32(ID)
+		follow = &target
+	}
+
+	conn, _, _, err := ws.UpgradeHTTP(r, w)
+	if err != nil {
+		room.logger().Error("websocket upgrade failed", "err", err)
+		http.Error(w, "Failed to connect", http.StatusInternalServerError)
+		return
+	}
+
+	var out = newOutbox(conn)
+	room.mu.Lock()
+	var spectator = room.addSpectator(&conn, out, follow, false)
+	if spectator != nil {
+		room.welcomeSpectator(spectator)
+	}
+	room.mu.Unlock()
+	if spectator == nil {
+		data, _ := proto.Marshal(&pb.Message{Event: SERVER_MESSAGE, Payload: &pb.Payload{Text: proto.String("Too many spectators")}})
+		out.send(SERVER_MESSAGE, data)
+		out.finish()
+		return
+	}
+	go handleSpectatorConnection(spectator, room)
+}
+
+// handleSpectatorConnection reads a spectator's messages until they disconnect.
+func handleSpectatorConnection(spectator *Spectator, room *Room) {
+	defer room.removeSpectator(spectator.ID)
+	room.logger().Info("spectator joined", "spectator", spectator.ID)
+
+	for {
+		data, err := wsutil.ReadClientBinary(*spectator.Conn)
+		if err != nil {
+			room.logger().Debug("spectator connection closed", "spectator", spectator.ID, "err", err)
+			return
+		}
+		var msg pb.Message
+		if err := proto.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		countReceived(msg.Event)
+		room.handleSpectatorMessage(spectator, &msg)
+	}
+}
+
+// eliminatePlayer takes a dead player out of last man standing, leaving them spectating.
+func (room *Room) eliminatePlayer(msg *pb.Message) {
+	var ID = *msg.Id
+	room.mu.Lock()
+	var player = room.player[ID]
+	if player == nil || !room.IsGameStarted || room.isOver {
+		room.mu.Unlock()
+		return
+	}
+	player.mu.Lock()
+	if !player.IsDead || room.hasRespawns() {
+		player.mu.Unlock()
+		room.mu.Unlock()
+		return
+	}
+	var result, _ = proto.Marshal(&pb.Message{
+		Id:      &ID,
+		Event:   KICK,
+		Payload: &pb.Payload{Kills: &player.Kills, Deaths: &player.Deaths},
+	})
+	var spectator *Spectator
+	if player.Conn != nil {
+		var killer *int32
+		if msg.Payload != nil {
+			killer = msg.Payload.Target
+		}
+		// keeping the player's queue keeps what was sent to them in order
+		spectator = room.addSpectator(player.Conn, player.outbox, killer, true)
+		player.spectator = spectator
+		player.Conn, player.outbox = nil, nil
+	}
+	player.mu.Unlock()
+	room.player[ID] = nil
+	room.index.remove(ID)
+	room.unfollow(ID)
+	if spectator != nil {
+		spectator.send(KICK, result)
+		room.welcomeSpectator(spectator)
+	}
+	var gameOver = room.remainingSides(ID) <= 1 || !room.hasHumans(ID)
+	room.mu.Unlock()
+	room.playerLogger(ID).Info("player eliminated", "spectating", spectator != nil)
+
+	room.broadcastParallel(&pb.Message{Id: &ID, Event: KICK})
+	if gameOver {
+		go room.broadcastGameOver()
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gobwas/ws/wsutil"
+	"google.golang.org/protobuf/proto"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+func spectatorRoom(t *testing.T) *Room {
+	var gameMap = generateMap(5, defaultMapParams())
+	return &Room{
+		ID:            1,
+		IsGameStarted: true,
+		gameMap:       gameMap,
+		settings:      &pb.GameSettings{Mode: sim.MODE_LAST_MAN_STANDING},
+		pickups:       newPickupState(gameMap),
+		index:         newPlayerIndex(gameMap),
+		obstacles:     sim.NewObstacleIndex(gameMap),
+		recorder:      newRecorder(t.TempDir()),
+		broadcast:     make(chan *pb.Message, 16),
+	}
+}
+
+// pipeConn returns a server side connection whose client reads into the
+// channel until the test ends.
+func pipeConn(t *testing.T) (*net.Conn, <-chan *pb.Message) {
+	server, client := net.Pipe()
+	t.Cleanup(func() { server.Close(); client.Close() })
+	var messages = make(chan *pb.Message, 64)
+	go func() {
+		for {
+			data, err := wsutil.ReadServerBinary(client)
+			if err != nil {
+				return
+			}
+			var msg pb.Message
+			if proto.Unmarshal(data, &msg) == nil {
+				messages <- &msg
+			}
+		}
+	}()
+	return &server, messages
+}
+
+// waitFor returns the first message with the event, skipping others.
+func waitFor(t *testing.T, messages <-chan *pb.Message, event string) *pb.Message {
+	t.Helper()
+	var timeout = time.After(time.Second)
+	for {
+		select {
+		case msg := <-messages:
+			if msg.Event == event {
+				return msg
+			}
+		case <-timeout:
+			t.Fatalf("no %s received", event)
+		}
+	}
+}
+
+func TestSpectatorCap(t *testing.T) {
+	t.Setenv("MAX_SPECTATORS", "1")
+	var room = spectatorRoom(t)
+	var first, _ = pipeConn(t)
+	var second, _ = pipeConn(t)
+	var third, _ = pipeConn(t)
+	if room.addSpectator(first, newOutbox(*first), nil, false) == nil {
+		t.Fatal("first spectator turned away")
+	}
+	if room.addSpectator(second, newOutbox(*second), nil, false) != nil {
+		t.Fatal("spectator let in over the cap")
+	}
+	if room.addSpectator(third, newOutbox(*third), nil, true) == nil {
+		t.Fatal("eliminated player turned away")
+	}
+}
+
+func TestStalledConnectionIsDropped(t *testing.T) {
+	var server, client = net.Pipe()
+	t.Cleanup(func() { client.Close() })
+	var out = newOutbox(server)
+
+	// nothing reads from client, so the first write never finishes
+	var sent = make(chan struct{})
+	go func() {
+		for i := 0; i <= OUTBOX_SIZE+1; i++ {
+			out.send(MOVE, []byte{1})
+		}
+		close(sent)
+	}()
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("send blocked on a stalled connection")
+	}
+	select {
+	case <-out.done:
+	case <-time.After(time.Second):
+		t.Fatal("a connection with a full queue wasn't dropped")
+	}
+	if _, err := client.Write([]byte{1}); err == nil {
+		t.Fatal("the dropped connection is still open")
+	}
+}
+
+func TestEliminatedPlayerSpectates(t *testing.T) {
+	var room = spectatorRoom(t)
+	var inbox [3]<-chan *pb.Message
+	for ID := range inbox {
+		var player = &Player{}
+		initializePlayer(player, int32(ID), room.spawnPosition(int32(ID)))
+		var conn *net.Conn
+		conn, inbox[ID] = pipeConn(t)
+		player.connect(conn)
+		room.player[ID] = player
+	}
+
+	room.player[0].IsDead = true
+	var killer int32 = 1
+	room.eliminatePlayer(&pb.Message{Id: proto.Int32(0), Event: ELIMINATED, Payload: &pb.Payload{Target: &killer}})
+
+	var player = room.player[0]
+	if player != nil {
+		t.Fatal("eliminated player still has a slot")
+	}
+	if msg := waitFor(t, inbox[1], KICK); msg.GetId() != 0 {
+		t.Fatalf("players were sent %v, want player 0 kicked", msg)
+	}
+	if msg := waitFor(t, inbox[0], KICK); msg.Payload.Kills == nil {
+		t.Fatalf("eliminated player got %v, want their result", msg)
+	}
+	var welcome = waitFor(t, inbox[0], SPECTATE)
+	if welcome.Payload.GetTarget() != killer || len(welcome.Payload.Players) != 2 {
+		t.Fatalf("SPECTATE = %v, want the 2 players left, following the killer", welcome)
+	}
+
+	var spectator = room.spectators[0]
+	if spectator == nil || !spectator.eliminated {
+		t.Fatal("eliminated player isn't a spectator")
+	}
+	room.handleSpectatorMessage(spectator, &pb.Message{Event: MOVE, Payload: &pb.Payload{Position: &pb.Position{X: 1}}})
+	if len(room.broadcast) != 0 {
+		t.Fatal("a spectator's MOVE reached the room")
+	}
+
+	var target int32 = 2
+	room.handleSpectatorMessage(spectator, &pb.Message{Event: FOLLOW, Payload: &pb.Payload{Target: &target}})
+	if msg := waitFor(t, inbox[0], FOLLOW); msg.Payload.GetTarget() != 2 {
+		t.Fatalf("FOLLOW = %v, want 2", msg)
+	}
+	room.removePlayer(2)
+	if msg := waitFor(t, inbox[0], FOLLOW); msg.Payload.GetTarget() != 1 {
+		t.Fatalf("FOLLOW after player 2 left = %v, want 1", msg)
+	}
+
+	// moves still queued from players who are gone, or without a position,
+	// are dropped
+	room.broadcastMove(&pb.Message{Id: proto.Int32(0), Event: MOVE, Payload: &pb.Payload{Position: &pb.Position{X: 1}}})
+	room.broadcastMove(&pb.Message{Id: proto.Int32(1), Event: MOVE})
+	room.setReady(&pb.Message{Id: proto.Int32(2), Event: READY, Payload: &pb.Payload{IsReady: proto.Bool(true)}})
+}
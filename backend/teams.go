@@ -0,0 +1,125 @@
+package main
+
+import (
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+// balancedTeam returns the team with the fewest players, skipping player ID.
+// The caller must hold room.mu.
+func (room *Room) balancedTeam(ID int32) int32 {
+	var counts [sim.TEAM_COUNT + 1]int
+	for _, player := range room.player {
+		if player == nil || player.Id == ID {
+			continue
+		}
+		player.mu.RLock()
+		if sim.IsValidTeam(player.Team) {
+			counts[player.Team]++
+		}
+		player.mu.RUnlock()
+	}
+
+	var team int32 = sim.TEAM_RED
+	for candidate := int32(sim.TEAM_RED); candidate <= sim.TEAM_COUNT; candidate++ {
+		if counts[candidate] < counts[team] {
+			team = candidate
+		}
+	}
+	return team
+}
+
+// changeTeam handles a TEAM request from the lobby. Passing no team asks the
+// server to auto-balance the player.
+func (room *Room) changeTeam(msg *pb.Message) {
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+	var player = room.player[*msg.Id]
+	if player == nil || !room.settings.Teams || room.IsGameStarted {
+		return
+	}
+
+	var team int32
+	if msg.Payload != nil && msg.Payload.Team != nil {
+		team = *msg.Payload.Team
+	} else {
+		team = room.balancedTeam(*msg.Id)
+	}
+	if !sim.IsValidTeam(team) {
+		return
+	}
+
+	player.mu.Lock()
+	player.Team = team
+	player.mu.Unlock()
+
+	room.playerLogger(*msg.Id).Info("player changed team", "team", team)
+	go room.broadcastParallel(&pb.Message{
+		Id:      msg.Id,
+		Event:   TEAM,
+		Payload: &pb.Payload{Team: &team},
+	})
+}
+
+// isFriendlyFire reports whether a hit between these teams is between
+// teammates and must be ignored.
+func (room *Room) isFriendlyFire(shooterTeam, targetTeam int32) bool {
+	return sim.IsFriendlyFire(room.settings, shooterTeam, targetTeam)
+}
+
+// teamScores sums kills, deaths and players per team. The caller must hold
+// room.mu and no player's lock.
+func (room *Room) teamScores() []*pb.TeamScore {
+	var scores []*pb.TeamScore
+	for team := int32(sim.TEAM_RED); team <= sim.TEAM_COUNT; team++ {
+		scores = append(scores, &pb.TeamScore{Team: team})
+	}
+	for _, player := range room.player {
+		if player == nil {
+			continue
+		}
+		player.mu.RLock()
+		if sim.IsValidTeam(player.Team) {
+			score := scores[player.Team-1]
+			score.Kills += player.Kills
+			score.Deaths += player.Deaths
+			score.Players++
+		}
+		player.mu.RUnlock()
+	}
+	if room.isCaptureTheFlag() {
+		room.ctf.mu.Lock()
+		for i, score := range scores {
+			score.Captures = room.ctf.captures[i]
+		}
+		room.ctf.mu.Unlock()
+	}
+	if room.isKingOfTheHill() {
+		room.koth.mu.Lock()
+		for i, score := range scores {
+			score.Score = room.koth.teamPoints[i]
+		}
+		room.koth.mu.Unlock()
+	}
+	return scores
+}
+
+// remainingSides counts the teams (or players, when teams are off) still in
+// the room once player leaving is gone. The caller must hold room.mu.
+func (room *Room) remainingSides(leaving int32) int {
+	var teams = map[int32]bool{}
+	var players int
+	for _, player := range room.player {
+		if player == nil || player.Id == leaving {
+			continue
+		}
+		players++
+		player.mu.RLock()
+		teams[player.Team] = true
+		player.mu.RUnlock()
+	}
+	if room.settings.Teams {
+		return len(teams)
+	}
+	return players
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"log/slog"
+	"math/big"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+const CERT_RELOAD_INTERVAL = 30 * time.Second
+
+// certReloader serves the certificate pair from disk and swaps it in place
+// whenever either file changes, so renewed certs don't need a restart.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	cert     *tls.Certificate
+	modTime  time.Time
+	mu       sync.RWMutex
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	reloader := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := reloader.reload(); err != nil {
+		return nil, err
+	}
+	go reloader.watch()
+	return reloader, nil
+}
+
+func (reloader *certReloader) latestModTime() (time.Time, error) {
+	certInfo, err := os.Stat(reloader.certFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	keyInfo, err := os.Stat(reloader.keyFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if keyInfo.ModTime().After(certInfo.ModTime()) {
+		return keyInfo.ModTime(), nil
+	}
+	return certInfo.ModTime(), nil
+}
+
+func (reloader *certReloader) reload() error {
+	modTime, err := reloader.latestModTime()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(reloader.certFile, reloader.keyFile)
+	if err != nil {
+		return err
+	}
+	reloader.mu.Lock()
+	reloader.cert = &cert
+	reloader.modTime = modTime
+	reloader.mu.Unlock()
+	return nil
+}
+
+func (reloader *certReloader) watch() {
+	ticker := time.NewTicker(CERT_RELOAD_INTERVAL)
+	defer ticker.Stop()
+	for range ticker.C {
+		modTime, err := reloader.latestModTime()
+		if err != nil {
+			continue
+		}
+		reloader.mu.RLock()
+		changed := modTime.After(reloader.modTime)
+		reloader.mu.RUnlock()
+		if changed {
+			// keep serving the previous pair if the new one is half-written
+			if err := reloader.reload(); err != nil {
+				slog.Error("failed to reload TLS certificate", "cert", reloader.certFile, "err", err)
+				continue
+			}
+			slog.Info("reloaded TLS certificate", "cert", reloader.certFile)
+		}
+	}
+}
+
+func (reloader *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	reloader.mu.RLock()
+	defer reloader.mu.RUnlock()
+	return reloader.cert, nil
+}
+
+// generateSelfSignedCert creates an in-memory certificate for localhost so
+// `wss://` can be tested locally without provisioning real certificates.
+func generateSelfSignedCert() (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"Battle Arena Dev"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// loadTLSConfig returns nil when TLS is disabled. TLS_SELF_SIGNED takes
+// precedence over TLS_CERT_FILE/TLS_KEY_FILE.
+func loadTLSConfig() (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if os.Getenv("TLS_SELF_SIGNED") == "true" {
+		cert, err := generateSelfSignedCert()
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{*cert}
+		return config, nil
+	}
+
+	certFile, keyFile := os.Getenv("TLS_CERT_FILE"), os.Getenv("TLS_KEY_FILE")
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	reloader, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	config.GetCertificate = reloader.GetCertificate
+	return config, nil
+}
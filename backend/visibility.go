@@ -0,0 +1,188 @@
+package main
+
+import (
+	"math"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// players closer than this are seen even in grass or behind an obstacle
+const REVEAL_DISTANCE = 4 * sim.PLAYER_SIZE
+
+// bullets this close to their shooter are only sent to those who can see them
+const SHOT_REVEAL_RANGE = 2 * REVEAL_DISTANCE
+
+// sighting is what a visibility pass knows about a player.
+type sighting struct {
+	present  bool
+	position *pb.Position
+	rotation float64
+	inGrass  bool
+	team     int32
+}
+
+// isVisible reports whether a player at from can see one at to.
+func isVisible(obstacles *sim.ObstacleIndex, from, to *pb.Position, inGrass bool) bool {
+	if math.Hypot(to.X-from.X, to.Y-from.Y) <= REVEAL_DISTANCE {
+		return true
+	}
+	return !inGrass && obstacles.HasLineOfSight(from, to)
+}
+
+// canSee reports whether viewer's client may be told where target is.
+func (room *Room) canSee(viewer, target *sighting) bool {
+	if !viewer.present || !target.present || viewer.position == nil || target.position == nil {
+		return false
+	}
+	if room.settings.Teams && sim.IsValidTeam(viewer.team) && viewer.team == target.team {
+		return true
+	}
+	return isVisible(room.obstacles, viewer.position, target.position, target.inGrass)
+}
+
+// sightings snapshots every player for a visibility pass.
+func (room *Room) sightings() [6]sighting {
+	var sightings [6]sighting
+	for i, player := range room.player {
+		if player == nil {
+			continue
+		}
+		player.mu.RLock()
+		sightings[i] = sighting{
+			present:  true,
+			position: player.Position,
+			rotation: player.Rotation,
+			inGrass:  player.InGrass,
+			team:     player.Team,
+		}
+		player.mu.RUnlock()
+	}
+	return sightings
+}
+
+// broadcastVisible sends a MOVE, and HIDDEN or MOVE to the viewers whose sight it changes.
+func (room *Room) broadcastVisible(msg *pb.Message) {
+	var moverID = *msg.Id
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		room.logger().Error("failed to marshal message", "event", msg.Event, "err", err)
+		return
+	}
+	// replays show everyone, like a spectator sees them
+	room.recorder.record(msg, false)
+	room.sendSpectators(msg.Event, data)
+
+	// one pass at a time, so clients get positions in the order they changed
+	room.sight.Lock()
+	defer room.sight.Unlock()
+	var sightings = room.sightings()
+	for _, viewer := range room.player {
+		if viewer == nil || viewer.isBot() {
+			continue
+		}
+		viewer.mu.Lock()
+		if viewer.Id != moverID {
+			room.updateSight(viewer, moverID, true, &sightings)
+		} else {
+			viewer.send(msg.Event, data)
+			for ID := range sightings {
+				if int32(ID) != moverID {
+					room.updateSight(viewer, int32(ID), false, &sightings)
+				}
+			}
+		}
+		viewer.mu.Unlock()
+	}
+}
+
+// updateSight tells viewer where player ID is, or that they lost sight of them.
+func (room *Room) updateSight(viewer *Player, ID int32, moved bool, sightings *[6]sighting) {
+	var target = &sightings[ID]
+	var visible = room.canSee(&sightings[viewer.Id], target)
+	var frame *pb.Message
+	switch {
+	case visible && (moved || !viewer.seen[ID]):
+		var rotation = target.rotation
+		var inGrass = target.inGrass
+		frame = &pb.Message{
+			Id:      &ID,
+			Event:   MOVE,
+			Payload: &pb.Payload{Position: target.position, Rotation: &rotation, InGrass: &inGrass},
+		}
+	case !visible && viewer.seen[ID] && target.present:
+		frame = &pb.Message{Id: &ID, Event: HIDDEN}
+	}
+	viewer.seen[ID] = visible
+	if frame == nil {
+		return
+	}
+	data, err := proto.Marshal(frame)
+	if err != nil {
+		room.playerLogger(viewer.Id).Error("failed to marshal message", "event", frame.Event, "err", err)
+		return
+	}
+	viewer.send(frame.Event, data)
+}
+
+// broadcastSighted sends msg to the clients who can see player ID and hidden to the rest.
+func (room *Room) broadcastSighted(ID int32, msg, hidden *pb.Message) [6]bool {
+	room.sight.Lock()
+	defer room.sight.Unlock()
+	return room.sendSighted([]int32{ID}, msg, hidden)
+}
+
+// sendSighted is broadcastSighted for several players. The caller holds room.sight.
+func (room *Room) sendSighted(IDs []int32, msg, hidden *pb.Message) [6]bool {
+	var visible [6]bool
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		room.logger().Error("failed to marshal message", "event", msg.Event, "err", err)
+		return visible
+	}
+	var hiddenData []byte
+	if hidden != nil {
+		if hiddenData, err = proto.Marshal(hidden); err != nil {
+			room.logger().Error("failed to marshal message", "event", hidden.Event, "err", err)
+			hidden = nil
+		}
+	}
+	room.recorder.record(msg, false)
+	room.sendSpectators(msg.Event, data)
+
+	var sightings = room.sightings()
+	for _, viewer := range room.player {
+		if viewer == nil {
+			continue
+		}
+		for _, ID := range IDs {
+			visible[viewer.Id] = visible[viewer.Id] || room.canSee(&sightings[viewer.Id], &sightings[ID])
+		}
+		if viewer.isBot() || (!visible[viewer.Id] && hidden == nil) {
+			continue
+		}
+		var event, payload = msg.Event, data
+		if !visible[viewer.Id] {
+			event, payload = hidden.Event, hiddenData
+		}
+		viewer.mu.RLock()
+		viewer.send(event, payload)
+		viewer.mu.RUnlock()
+	}
+	return visible
+}
+
+// markSeen records that every client was just told where player ID is.
+func (room *Room) markSeen(ID int32) {
+	room.sight.Lock()
+	defer room.sight.Unlock()
+	for _, viewer := range room.player {
+		if viewer != nil {
+			viewer.mu.Lock()
+			viewer.seen[ID] = true
+			viewer.mu.Unlock()
+		}
+	}
+}
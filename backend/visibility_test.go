@@ -0,0 +1,93 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "battle-arena/message"
+)
+
+func TestBroadcastSightedHidesPlayersInGrass(t *testing.T) {
+	var room = spectatorRoom(t)
+	var inbox [2]<-chan *pb.Message
+	for ID := range inbox {
+		var player = &Player{}
+		initializePlayer(player, int32(ID), room.spawnPosition(int32(ID)))
+		var conn *net.Conn
+		conn, inbox[ID] = pipeConn(t)
+		player.connect(conn)
+		room.player[ID] = player
+	}
+	room.player[0].Position = &pb.Position{X: 100, Y: 100}
+	room.player[1].Position = &pb.Position{X: 100 + 2*REVEAL_DISTANCE, Y: 100}
+	room.player[1].InGrass = true
+
+	var ID int32 = 1
+	var msg = &pb.Message{Id: &ID, Event: RESPAWN, Payload: &pb.Payload{Players: []*pb.Player{{Id: 1, Position: room.player[1].Position}}}}
+	var hidden = &pb.Message{Id: &ID, Event: RESPAWN, Payload: &pb.Payload{Players: []*pb.Player{{Id: 1}}}}
+	room.mu.RLock()
+	var visible = room.broadcastSighted(1, msg, hidden)
+	room.mu.RUnlock()
+	if visible[0] || !visible[1] {
+		t.Fatalf("visible = %v, want only player 1 to see themselves", visible)
+	}
+	if got := waitFor(t, inbox[0], RESPAWN); got.Payload.Players[0].Position != nil {
+		t.Fatalf("player 0 was told where player 1 is: %v", got)
+	}
+	if got := waitFor(t, inbox[1], RESPAWN); !proto.Equal(got, msg) {
+		t.Fatalf("player 1 got %v, want %v", got, msg)
+	}
+
+	room.mu.RLock()
+	room.broadcastSighted(1, &pb.Message{Id: &ID, Event: SHOOT}, nil)
+	room.broadcastSighted(0, &pb.Message{Event: SHOOT, Payload: &pb.Payload{Ammo: proto.Int32(3)}}, nil)
+	room.mu.RUnlock()
+	if got := waitFor(t, inbox[0], SHOOT); got.GetId() == 1 {
+		t.Fatal("player 0 was sent a hidden player's shot")
+	}
+}
+
+func TestBroadcastVisibleKeepsOrder(t *testing.T) {
+	var room = spectatorRoom(t)
+	var inbox [2]<-chan *pb.Message
+	for ID := range inbox {
+		var player = &Player{}
+		initializePlayer(player, int32(ID), room.spawnPosition(int32(ID)))
+		var conn *net.Conn
+		conn, inbox[ID] = pipeConn(t)
+		player.connect(conn)
+		room.player[ID] = player
+	}
+	room.player[0].Position = &pb.Position{X: 100, Y: 100}
+
+	var ID int32 = 1
+	for i := 0; i < 50; i++ {
+		// every other move goes into grass out of player 0's reach
+		var inGrass = i%2 == 1
+		room.player[1].Position = &pb.Position{X: 100 + 2*REVEAL_DISTANCE, Y: 100 + float64(i)}
+		room.player[1].InGrass = inGrass
+		room.mu.RLock()
+		room.broadcastVisible(&pb.Message{Id: &ID, Event: MOVE, Payload: &pb.Payload{Position: room.player[1].Position, InGrass: &inGrass}})
+		room.mu.RUnlock()
+	}
+	for i := 0; i < 50; i++ {
+		var want = MOVE
+		if i%2 == 1 {
+			want = HIDDEN
+		}
+		select {
+		case msg := <-inbox[0]:
+			if msg.Event != want {
+				t.Fatalf("frame %d is %s, want %s", i, msg.Event, want)
+			}
+			if want == MOVE && msg.Payload.Position.Y != 100+float64(i) {
+				t.Fatalf("frame %d is an old MOVE: %v", i, msg)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("frame %d never arrived", i)
+		}
+	}
+}
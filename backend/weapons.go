@@ -0,0 +1,249 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "battle-arena/message"
+	"battle-arena/sim"
+)
+
+// weaponAmmo is what's left in a weapon the player isn't holding.
+type weaponAmmo struct {
+	magazine int32
+	reserve  int32
+}
+
+// fire creates the weapon's projectiles leaving position at angle rotation.
+func fire(weapon *sim.Weapon, position *pb.Position, rotation float64, now time.Time) []*pb.Bullet {
+	var bullets []*pb.Bullet
+	for i, angle := range weapon.Angles(rotation, rand.Float64) {
+		bullets = append(bullets, &pb.Bullet{
+			// microseconds, as nanoseconds are beyond a float64's precision
+			Id:       float64(now.UnixMicro() + int64(i)),
+			Position: &pb.Position{X: position.X, Y: position.Y},
+			Rotation: angle,
+			Weapon:   weapon.Name,
+		})
+	}
+	return bullets
+}
+
+// resetWeapons gives the player the starting loadout, the default weapon with
+// a full magazine and spare ammo. Weapons picked up are lost. The caller
+// holds player.mu.
+func resetWeapons(player *Player) {
+	var weapon = sim.WEAPONS[sim.DEFAULT_WEAPON]
+	player.Weapon = weapon.Name
+	player.Weapons = []string{weapon.Name}
+	player.Ammo, player.Reserve = weapon.FullLoad()
+	player.holstered = nil
+	player.Reloading = false
+	player.reloadGeneration++
+}
+
+// fireWeapon handles a SHOOT from a player, respecting the fire rate and the
+// magazine of the weapon they hold. Emptying the magazine starts a reload
+// when there's spare ammo for it.
+func (room *Room) fireWeapon(msg *pb.Message) {
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+	var player = room.player[*msg.Id]
+	if player == nil {
+		return
+	}
+
+	var now = time.Now()
+	player.mu.Lock()
+	var weapon = sim.WeaponByName(player.Weapon)
+	if player.IsDead || player.Reloading || now.Sub(player.lastShot) < weapon.FireRate {
+		player.mu.Unlock()
+		return
+	}
+	var fired, reload bool
+	player.Ammo, fired, reload = sim.PullTrigger(player.Ammo, player.Reserve)
+	var ammo = player.Ammo
+	var bullets []*pb.Bullet
+	if fired {
+		player.lastShot = now
+		bullets = fire(weapon, player.Position, player.Rotation, now)
+	}
+	if reload {
+		room.startReload(player)
+	}
+	player.mu.Unlock()
+
+	for _, bullet := range bullets {
+		room.broadcastSighted(*msg.Id, &pb.Message{
+			Id:      msg.Id,
+			Event:   SHOOT,
+			Payload: &pb.Payload{Bullet: proto.Clone(bullet).(*pb.Bullet), Ammo: &ammo},
+		}, nil)
+		go room.handleBulletMovement(bullet, msg.Id)
+	}
+}
+
+// reloadWeapon handles a RELOAD request for the held weapon.
+func (room *Room) reloadWeapon(msg *pb.Message) {
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+	var player = room.player[*msg.Id]
+	if player == nil {
+		return
+	}
+
+	player.mu.Lock()
+	defer player.mu.Unlock()
+	if !player.IsDead && !player.Reloading && sim.WeaponByName(player.Weapon).CanReload(player.Ammo, player.Reserve) {
+		room.startReload(player)
+	}
+}
+
+// startReload refills the held weapon's magazine from its reserve after its
+// reload time, unless the player switches weapons or respawns first. The
+// caller holds room.mu and player.mu.
+func (room *Room) startReload(player *Player) {
+	player.Reloading = true
+	player.reloadGeneration++
+	var ID, generation = player.Id, player.reloadGeneration
+	time.AfterFunc(sim.WeaponByName(player.Weapon).ReloadTime, func() {
+		room.finishReload(ID, generation)
+	})
+
+	var reloading = true
+	go room.broadcastParallel(&pb.Message{
+		Id:      &ID,
+		Event:   RELOAD,
+		Payload: &pb.Payload{Reloading: &reloading},
+	})
+}
+
+func (room *Room) finishReload(ID int32, generation uint64) {
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+	var player = room.player[ID]
+	if player == nil {
+		return
+	}
+
+	player.mu.Lock()
+	if !player.Reloading || player.reloadGeneration != generation {
+		player.mu.Unlock()
+		return
+	}
+	player.Reloading = false
+	player.Ammo, player.Reserve = sim.WeaponByName(player.Weapon).Refill(player.Ammo, player.Reserve)
+	var ammo, reserve, reloading = player.Ammo, player.Reserve, false
+	player.mu.Unlock()
+
+	go room.broadcastParallel(&pb.Message{
+		Id:      &ID,
+		Event:   RELOAD,
+		Payload: &pb.Payload{Reloading: &reloading, Ammo: &ammo, Reserve: &reserve},
+	})
+}
+
+// switchWeapon handles a WEAPON request for a weapon the player carries.
+// Each weapon keeps its own magazine and reserve; switching cancels a
+// reload, and drawing an empty weapon starts one.
+func (room *Room) switchWeapon(msg *pb.Message) {
+	if msg.Payload == nil || msg.Payload.Weapon == nil {
+		return
+	}
+	var name = *msg.Payload.Weapon
+	weapon, ok := sim.WEAPONS[name]
+	if !ok {
+		return
+	}
+
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+	var player = room.player[*msg.Id]
+	if player == nil {
+		return
+	}
+
+	player.mu.Lock()
+	if player.IsDead || player.Weapon == name || !slices.Contains(player.Weapons, name) {
+		player.mu.Unlock()
+		return
+	}
+	if player.holstered == nil {
+		player.holstered = map[string]weaponAmmo{}
+	}
+	player.holstered[player.Weapon] = weaponAmmo{magazine: player.Ammo, reserve: player.Reserve}
+	player.Weapon = name
+	if held, ok := player.holstered[name]; ok {
+		player.Ammo, player.Reserve = held.magazine, held.reserve
+		delete(player.holstered, name)
+	} else {
+		player.Ammo, player.Reserve = weapon.FullLoad()
+	}
+	player.Reloading = false
+	player.reloadGeneration++
+	if sim.NeedsReload(player.Ammo, player.Reserve) {
+		room.startReload(player)
+	}
+	var ammo, reserve = player.Ammo, player.Reserve
+	player.mu.Unlock()
+
+	room.playerLogger(*msg.Id).Debug("player switched weapon", "weapon", name)
+	go room.broadcastParallel(&pb.Message{
+		Id:      msg.Id,
+		Event:   WEAPON,
+		Payload: &pb.Payload{Weapon: &name, Ammo: &ammo, Reserve: &reserve},
+	})
+}
+
+// addReserve gives the player up to amount spare rounds for a weapon they
+// carry and reports whether any fit. The caller holds player.mu.
+func addReserve(player *Player, name string, amount int32) bool {
+	var limit = sim.WeaponByName(name).MaxReserve
+	if name == player.Weapon {
+		var added = min(amount, limit-player.Reserve)
+		if added <= 0 {
+			return false
+		}
+		player.Reserve += added
+		return true
+	}
+	var held = player.holstered[name]
+	var added = min(amount, limit-held.reserve)
+	if added <= 0 {
+		return false
+	}
+	held.reserve += added
+	player.holstered[name] = held
+	return true
+}
+
+// giveWeapon adds a weapon to the player's inventory with a full magazine
+// and spare ammo, or only the spare ammo when they already carry it. It
+// reports whether the player took anything. The caller holds player.mu.
+func giveWeapon(player *Player, name string) bool {
+	var magazine, reserve = sim.WEAPONS[name].FullLoad()
+	if slices.Contains(player.Weapons, name) {
+		return addReserve(player, name, reserve)
+	}
+	if player.holstered == nil {
+		player.holstered = map[string]weaponAmmo{}
+	}
+	player.Weapons = append(player.Weapons, name)
+	player.holstered[name] = weaponAmmo{magazine: magazine, reserve: reserve}
+	return true
+}
+
+// giveAmmo adds spare magazines to every weapon the player carries and
+// reports whether any of them had room. The caller holds player.mu.
+func giveAmmo(player *Player) bool {
+	var took bool
+	for _, name := range player.Weapons {
+		if _, reserve := sim.WEAPONS[name].FullLoad(); addReserve(player, name, reserve) {
+			took = true
+		}
+	}
+	return took
+}